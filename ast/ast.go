@@ -1,12 +1,20 @@
 // Package ast contains the types used to build the AST (abstract syntax tree) for some Monkey source code.
 package ast
 
-import "github.com/marcuscaisey/monkey/token"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/marcuscaisey/monkey/token"
+)
 
 // Node is the interface that all ast nodes implement.
 type Node interface {
 	// TokenLiteral returns the literal value of the token associated with the node.
 	TokenLiteral() string
+	// String returns the Monkey source code that the node represents. Expressions which contain operators are fully
+	// parenthesised so that the structure of the AST is unambiguous.
+	String() string
 }
 
 // Statement is the interface that all statement nodes implement.
@@ -26,6 +34,9 @@ type Expression interface {
 // Program is the root node of every AST.
 type Program struct {
 	Statements []Statement
+	// Comments are the comments in the source code of the program, in the order that they appear. They're only
+	// recorded if the lexer which the program was parsed from was emitting comments.
+	Comments []Comment
 }
 
 func (p Program) TokenLiteral() string {
@@ -35,6 +46,15 @@ func (p Program) TokenLiteral() string {
 	return ""
 }
 
+// String returns the source code of each statement on a separate line.
+func (p Program) String() string {
+	stmts := make([]string, len(p.Statements))
+	for i, stmt := range p.Statements {
+		stmts[i] = stmt.String()
+	}
+	return strings.Join(stmts, "\n")
+}
+
 // LetStatement is the node for a statement of the form
 //   let <identifier> = <expression>
 type LetStatement struct {
@@ -47,8 +67,175 @@ func (ls LetStatement) TokenLiteral() string {
 	return ls.Token.Literal
 }
 
+func (ls LetStatement) String() string {
+	return "let " + ls.Name.String() + " = " + ls.Value.String() + ";"
+}
+
 func (ls LetStatement) statementNode() {}
 
+// AssignStatement is the node for a statement of the form
+//   <identifier> = <expression>
+// which assigns a new value to an existing binding.
+type AssignStatement struct {
+	Token token.Token
+	Name  Identifier
+	Value Expression
+}
+
+func (as AssignStatement) TokenLiteral() string {
+	return as.Token.Literal
+}
+
+func (as AssignStatement) String() string {
+	return as.Name.String() + " = " + as.Value.String() + ";"
+}
+
+func (as AssignStatement) statementNode() {}
+
+// ReturnStatement is the node for a statement of the form
+//   return <expression>
+// ReturnValue is nil if the statement doesn't have an expression.
+type ReturnStatement struct {
+	Token       token.Token
+	ReturnValue Expression
+}
+
+func (rs ReturnStatement) TokenLiteral() string {
+	return rs.Token.Literal
+}
+
+func (rs ReturnStatement) String() string {
+	if rs.ReturnValue == nil {
+		return "return;"
+	}
+	return "return " + rs.ReturnValue.String() + ";"
+}
+
+func (rs ReturnStatement) statementNode() {}
+
+// ExpressionStatement is the node for a statement consisting of a single expression.
+type ExpressionStatement struct {
+	Token      token.Token
+	Expression Expression
+}
+
+func (es ExpressionStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+func (es ExpressionStatement) String() string {
+	return es.Expression.String() + ";"
+}
+
+func (es ExpressionStatement) statementNode() {}
+
+// WhileStatement is the node for a statement of the form
+//   <label>: while (<expression>) <block statement>
+// Label is empty if the loop doesn't have a label.
+type WhileStatement struct {
+	Token     token.Token
+	Label     string
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+
+func (ws WhileStatement) String() string {
+	var label string
+	if ws.Label != "" {
+		label = ws.Label + ": "
+	}
+	return label + "while (" + ws.Condition.String() + ") " + ws.Body.String()
+}
+
+func (ws WhileStatement) statementNode() {}
+
+// BreakStatement is the node for a statement of the form
+//   break <label>
+// Label is empty if the statement doesn't have a label, in which case it breaks out of the innermost loop.
+type BreakStatement struct {
+	Token token.Token
+	Label string
+}
+
+func (bs BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs BreakStatement) String() string {
+	if bs.Label == "" {
+		return "break;"
+	}
+	return "break " + bs.Label + ";"
+}
+
+func (bs BreakStatement) statementNode() {}
+
+// ContinueStatement is the node for a statement of the form
+//   continue <label>
+// Label is empty if the statement doesn't have a label, in which case it continues the innermost loop.
+type ContinueStatement struct {
+	Token token.Token
+	Label string
+}
+
+func (cs ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+func (cs ContinueStatement) String() string {
+	if cs.Label == "" {
+		return "continue;"
+	}
+	return "continue " + cs.Label + ";"
+}
+
+func (cs ContinueStatement) statementNode() {}
+
+// BlockStatement is the node for a sequence of statements enclosed in braces.
+type BlockStatement struct {
+	Token      token.Token
+	Statements []Statement
+	RBrace     token.Token // the closing brace
+}
+
+func (bs BlockStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs BlockStatement) String() string {
+	if len(bs.Statements) == 0 {
+		return "{}"
+	}
+	stmts := make([]string, len(bs.Statements))
+	for i, stmt := range bs.Statements {
+		stmts[i] = stmt.String()
+	}
+	return "{ " + strings.Join(stmts, " ") + " }"
+}
+
+func (bs BlockStatement) statementNode() {}
+
+// Comment is the node for a comment. Comments aren't part of the statements of a [Program] and are kept in its
+// Comments instead, so that they can be reproduced by tools like formatters.
+type Comment struct {
+	Token token.Token
+	// Trailing is whether the comment follows another token on the same line, like a comment at the end of a
+	// statement, rather than starting a line of its own.
+	Trailing bool
+}
+
+func (c Comment) TokenLiteral() string {
+	return c.Token.Literal
+}
+
+func (c Comment) String() string {
+	return c.Token.Literal
+}
+
 // Identifier is the node for an identifier.
 type Identifier struct {
 	Token token.Token
@@ -59,4 +246,236 @@ func (i Identifier) TokenLiteral() string {
 	return i.Token.Literal
 }
 
+func (i Identifier) String() string {
+	return i.Value
+}
+
 func (i Identifier) expressionNode() {}
+
+// IntegerLiteral is the node for an integer literal.
+type IntegerLiteral struct {
+	Token token.Token
+	Value int64
+}
+
+func (il IntegerLiteral) TokenLiteral() string {
+	return il.Token.Literal
+}
+
+// String returns the value of the literal in decimal, regardless of the base that it was written in.
+func (il IntegerLiteral) String() string {
+	return strconv.FormatInt(il.Value, 10)
+}
+
+func (il IntegerLiteral) expressionNode() {}
+
+// StringLiteral is the node for a string literal. Value is the contents of the literal with any escape sequences
+// decoded.
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (sl StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+
+// stringEscaper escapes the characters which have escape sequences in Monkey string literals.
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// String returns the value of the literal as a double quoted string literal, regardless of whether it was written as a
+// raw string literal.
+func (sl StringLiteral) String() string {
+	return `"` + stringEscaper.Replace(sl.Value) + `"`
+}
+
+func (sl StringLiteral) expressionNode() {}
+
+// Boolean is the node for the boolean literals true and false.
+type Boolean struct {
+	Token token.Token
+	Value bool
+}
+
+func (b Boolean) TokenLiteral() string {
+	return b.Token.Literal
+}
+
+func (b Boolean) String() string {
+	return strconv.FormatBool(b.Value)
+}
+
+func (b Boolean) expressionNode() {}
+
+// PrefixExpression is the node for an expression of the form
+//   <operator><expression>
+type PrefixExpression struct {
+	Token    token.Token
+	Operator string
+	Right    Expression
+}
+
+func (pe PrefixExpression) TokenLiteral() string {
+	return pe.Token.Literal
+}
+
+func (pe PrefixExpression) String() string {
+	return "(" + pe.Operator + pe.Right.String() + ")"
+}
+
+func (pe PrefixExpression) expressionNode() {}
+
+// InfixExpression is the node for an expression of the form
+//   <expression> <operator> <expression>
+type InfixExpression struct {
+	Token    token.Token
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (ie InfixExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie InfixExpression) String() string {
+	return "(" + ie.Left.String() + " " + ie.Operator + " " + ie.Right.String() + ")"
+}
+
+func (ie InfixExpression) expressionNode() {}
+
+// IfExpression is the node for an expression of the form
+//   if (<expression>) <block statement> else <block statement>
+// Alternative is nil if the expression doesn't have an else branch.
+type IfExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ie IfExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie IfExpression) String() string {
+	s := "if (" + ie.Condition.String() + ") " + ie.Consequence.String()
+	if ie.Alternative != nil {
+		s += " else " + ie.Alternative.String()
+	}
+	return s
+}
+
+func (ie IfExpression) expressionNode() {}
+
+// FunctionLiteral is the node for an expression of the form
+//   fn(<identifier>, <identifier>, ...) <block statement>
+type FunctionLiteral struct {
+	Token      token.Token
+	Parameters []Identifier
+	Body       *BlockStatement
+}
+
+func (fl FunctionLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+
+func (fl FunctionLiteral) String() string {
+	params := make([]string, len(fl.Parameters))
+	for i, param := range fl.Parameters {
+		params[i] = param.String()
+	}
+	return "fn(" + strings.Join(params, ", ") + ") " + fl.Body.String()
+}
+
+func (fl FunctionLiteral) expressionNode() {}
+
+// CallExpression is the node for an expression of the form
+//   <expression>(<expression>, <expression>, ...)
+// where Function is either an identifier or a function literal.
+type CallExpression struct {
+	Token     token.Token
+	Function  Expression
+	Arguments []Expression
+}
+
+func (ce CallExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+
+func (ce CallExpression) String() string {
+	args := make([]string, len(ce.Arguments))
+	for i, arg := range ce.Arguments {
+		args[i] = arg.String()
+	}
+	return ce.Function.String() + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (ce CallExpression) expressionNode() {}
+
+// ArrayLiteral is the node for an expression of the form
+//   [<expression>, <expression>, ...]
+type ArrayLiteral struct {
+	Token    token.Token
+	Elements []Expression
+}
+
+func (al ArrayLiteral) TokenLiteral() string {
+	return al.Token.Literal
+}
+
+func (al ArrayLiteral) String() string {
+	elements := make([]string, len(al.Elements))
+	for i, element := range al.Elements {
+		elements[i] = element.String()
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+func (al ArrayLiteral) expressionNode() {}
+
+// IndexExpression is the node for an expression of the form
+//   <expression>[<expression>]
+type IndexExpression struct {
+	Token token.Token
+	Left  Expression
+	Index Expression
+}
+
+func (ie IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie IndexExpression) String() string {
+	return "(" + ie.Left.String() + "[" + ie.Index.String() + "])"
+}
+
+func (ie IndexExpression) expressionNode() {}
+
+// HashLiteral is the node for an expression of the form
+//   {<expression>: <expression>, <expression>: <expression>, ...}
+// Pairs are stored in the order that they appear in the source.
+type HashLiteral struct {
+	Token token.Token
+	Pairs []HashPair
+}
+
+// HashPair is a key-value pair in a [HashLiteral].
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func (hl HashLiteral) TokenLiteral() string {
+	return hl.Token.Literal
+}
+
+func (hl HashLiteral) String() string {
+	pairs := make([]string, len(hl.Pairs))
+	for i, pair := range hl.Pairs {
+		pairs[i] = pair.Key.String() + ": " + pair.Value.String()
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func (hl HashLiteral) expressionNode() {}
@@ -0,0 +1,67 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/ast"
+)
+
+func TestString(t *testing.T) {
+	program := ast.Program{
+		Statements: []ast.Statement{
+			ast.LetStatement{
+				Name:  ast.Identifier{Value: "myVar"},
+				Value: ast.Identifier{Value: "anotherVar"},
+			},
+			ast.ReturnStatement{
+				ReturnValue: ast.InfixExpression{
+					Left:     ast.PrefixExpression{Operator: "-", Right: ast.IntegerLiteral{Value: 5}},
+					Operator: "*",
+					Right: ast.InfixExpression{
+						Left:     ast.Identifier{Value: "x"},
+						Operator: "+",
+						Right:    ast.IntegerLiteral{Value: 10},
+					},
+				},
+			},
+			ast.ReturnStatement{},
+			ast.LetStatement{
+				Name:  ast.Identifier{Value: "s"},
+				Value: ast.StringLiteral{Value: "say \"hi\"\n"},
+			},
+			ast.ExpressionStatement{
+				Expression: ast.PrefixExpression{Operator: "!", Right: ast.Identifier{Value: "ok"}},
+			},
+			ast.WhileStatement{
+				Condition: ast.Identifier{Value: "ok"},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						ast.AssignStatement{Name: ast.Identifier{Value: "ok"}, Value: ast.Boolean{Value: false}},
+					},
+				},
+			},
+			ast.WhileStatement{
+				Label:     "outer",
+				Condition: ast.Boolean{Value: true},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{ast.ContinueStatement{}, ast.BreakStatement{Label: "outer"}},
+				},
+			},
+		},
+	}
+	want := `let myVar = anotherVar;
+return ((-5) * (x + 10));
+return;
+let s = "say \"hi\"\n";
+(!ok);
+while (ok) { ok = false; }
+outer: while (true) { continue; break outer; }`
+
+	got := program.String()
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("String() returned incorrect source\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
@@ -0,0 +1,460 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcuscaisey/monkey/token"
+)
+
+// ToJSON returns the JSON representation of the given program. Each node is represented as an object with a "kind" key
+// containing the name of the node's type, like "LetStatement", and a key for each of the node's fields. The keys are
+// the names of the fields in lower camel case, like "returnValue", and child nodes are represented in the same way. Nil
+// child nodes are represented as null. Tokens are represented as described by [token.Token]. The comments of the
+// program aren't included.
+//
+// For example, the program
+//
+//	x;
+//
+// is represented as
+//
+//	{
+//	  "kind": "Program",
+//	  "statements": [
+//	    {
+//	      "kind": "ExpressionStatement",
+//	      "token": {"type": "IDENT", "literal": "x", "line": 1, "column": 1},
+//	      "expression": {
+//	        "kind": "Identifier",
+//	        "token": {"type": "IDENT", "literal": "x", "line": 1, "column": 1},
+//	        "value": "x"
+//	      }
+//	    }
+//	  ]
+//	}
+func ToJSON(program *Program) ([]byte, error) {
+	return json.Marshal(nodeToJSON(program))
+}
+
+// nodeToJSON returns a value which marshals to the JSON representation of the given node.
+func nodeToJSON(node Node) any {
+	switch node := node.(type) {
+	case nil:
+		return nil
+	case *Program:
+		return map[string]any{"kind": "Program", "statements": statementsToJSON(node.Statements)}
+	case LetStatement:
+		return map[string]any{
+			"kind":  "LetStatement",
+			"token": node.Token,
+			"name":  nodeToJSON(node.Name),
+			"value": nodeToJSON(node.Value),
+		}
+	case AssignStatement:
+		return map[string]any{
+			"kind":  "AssignStatement",
+			"token": node.Token,
+			"name":  nodeToJSON(node.Name),
+			"value": nodeToJSON(node.Value),
+		}
+	case ReturnStatement:
+		return map[string]any{"kind": "ReturnStatement", "token": node.Token, "returnValue": nodeToJSON(node.ReturnValue)}
+	case ExpressionStatement:
+		return map[string]any{"kind": "ExpressionStatement", "token": node.Token, "expression": nodeToJSON(node.Expression)}
+	case WhileStatement:
+		return map[string]any{
+			"kind":      "WhileStatement",
+			"token":     node.Token,
+			"label":     node.Label,
+			"condition": nodeToJSON(node.Condition),
+			"body":      blockToJSON(node.Body),
+		}
+	case BreakStatement:
+		return map[string]any{"kind": "BreakStatement", "token": node.Token, "label": node.Label}
+	case ContinueStatement:
+		return map[string]any{"kind": "ContinueStatement", "token": node.Token, "label": node.Label}
+	case BlockStatement:
+		return blockToJSON(&node)
+	case Identifier:
+		return map[string]any{"kind": "Identifier", "token": node.Token, "value": node.Value}
+	case IntegerLiteral:
+		return map[string]any{"kind": "IntegerLiteral", "token": node.Token, "value": node.Value}
+	case StringLiteral:
+		return map[string]any{"kind": "StringLiteral", "token": node.Token, "value": node.Value}
+	case Boolean:
+		return map[string]any{"kind": "Boolean", "token": node.Token, "value": node.Value}
+	case PrefixExpression:
+		return map[string]any{
+			"kind":     "PrefixExpression",
+			"token":    node.Token,
+			"operator": node.Operator,
+			"right":    nodeToJSON(node.Right),
+		}
+	case InfixExpression:
+		return map[string]any{
+			"kind":     "InfixExpression",
+			"token":    node.Token,
+			"left":     nodeToJSON(node.Left),
+			"operator": node.Operator,
+			"right":    nodeToJSON(node.Right),
+		}
+	case IfExpression:
+		return map[string]any{
+			"kind":        "IfExpression",
+			"token":       node.Token,
+			"condition":   nodeToJSON(node.Condition),
+			"consequence": blockToJSON(node.Consequence),
+			"alternative": blockToJSON(node.Alternative),
+		}
+	case FunctionLiteral:
+		params := make([]any, len(node.Parameters))
+		for i, param := range node.Parameters {
+			params[i] = nodeToJSON(param)
+		}
+		return map[string]any{
+			"kind":       "FunctionLiteral",
+			"token":      node.Token,
+			"parameters": params,
+			"body":       blockToJSON(node.Body),
+		}
+	case CallExpression:
+		return map[string]any{
+			"kind":      "CallExpression",
+			"token":     node.Token,
+			"function":  nodeToJSON(node.Function),
+			"arguments": expressionsToJSON(node.Arguments),
+		}
+	case ArrayLiteral:
+		return map[string]any{"kind": "ArrayLiteral", "token": node.Token, "elements": expressionsToJSON(node.Elements)}
+	case IndexExpression:
+		return map[string]any{
+			"kind":  "IndexExpression",
+			"token": node.Token,
+			"left":  nodeToJSON(node.Left),
+			"index": nodeToJSON(node.Index),
+		}
+	case HashLiteral:
+		pairs := make([]any, len(node.Pairs))
+		for i, pair := range node.Pairs {
+			pairs[i] = map[string]any{"key": nodeToJSON(pair.Key), "value": nodeToJSON(pair.Value)}
+		}
+		return map[string]any{"kind": "HashLiteral", "token": node.Token, "pairs": pairs}
+	default:
+		panic(fmt.Sprintf("ToJSON: unexpected node type %T", node))
+	}
+}
+
+func blockToJSON(block *BlockStatement) any {
+	if block == nil {
+		return nil
+	}
+	return map[string]any{
+		"kind":       "BlockStatement",
+		"token":      block.Token,
+		"statements": statementsToJSON(block.Statements),
+		"rbrace":     block.RBrace,
+	}
+}
+
+func statementsToJSON(stmts []Statement) []any {
+	result := make([]any, len(stmts))
+	for i, stmt := range stmts {
+		result[i] = nodeToJSON(stmt)
+	}
+	return result
+}
+
+func expressionsToJSON(exprs []Expression) []any {
+	result := make([]any, len(exprs))
+	for i, expr := range exprs {
+		result[i] = nodeToJSON(expr)
+	}
+	return result
+}
+
+// FromJSON returns the program which has the given JSON representation, as returned by [ToJSON]. An error is returned
+// if data isn't a valid representation of a program.
+func FromJSON(data []byte) (*Program, error) {
+	d := &jsonDecoder{}
+	var program *Program
+	fields := d.object(data)
+	switch {
+	case d.err != nil:
+	case fields == nil:
+		d.err = fmt.Errorf("expected Program node, got null")
+	case d.kind(fields) != "Program":
+		d.err = fmt.Errorf("expected Program node, got %s", d.kind(fields))
+	default:
+		program = &Program{Statements: d.statements(fields, "statements")}
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("decoding AST from JSON: %w", d.err)
+	}
+	return program, nil
+}
+
+// jsonDecoder decodes nodes from their JSON representation. Once an error has been encountered, it's stored in err
+// and all further decoding is skipped, so that errors only have to be checked once at the end.
+type jsonDecoder struct {
+	err error
+}
+
+// object decodes a JSON object into its fields. nil is returned if the object is null.
+func (d *jsonDecoder) object(data json.RawMessage) map[string]json.RawMessage {
+	if d.err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		d.err = err
+	}
+	return fields
+}
+
+// field decodes the field with the given key into v.
+func (d *jsonDecoder) field(fields map[string]json.RawMessage, key string, v any) {
+	if d.err != nil {
+		return
+	}
+	data, ok := fields[key]
+	if !ok {
+		d.err = fmt.Errorf("%s node is missing %q field", d.kind(fields), key)
+		return
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		d.err = fmt.Errorf("%s node has invalid %q field: %w", d.kind(fields), key, err)
+	}
+}
+
+func (d *jsonDecoder) kind(fields map[string]json.RawMessage) string {
+	var kind string
+	if err := json.Unmarshal(fields["kind"], &kind); err != nil {
+		return "unknown"
+	}
+	return kind
+}
+
+func (d *jsonDecoder) token(fields map[string]json.RawMessage) token.Token {
+	return d.tokenField(fields, "token")
+}
+
+func (d *jsonDecoder) tokenField(fields map[string]json.RawMessage, key string) token.Token {
+	var tok token.Token
+	d.field(fields, key, &tok)
+	return tok
+}
+
+func (d *jsonDecoder) string(fields map[string]json.RawMessage, key string) string {
+	var s string
+	d.field(fields, key, &s)
+	return s
+}
+
+// node decodes the node in the field with the given key. nil is returned if the field is null.
+func (d *jsonDecoder) node(fields map[string]json.RawMessage, key string) Node {
+	var data json.RawMessage
+	d.field(fields, key, &data)
+	if d.err != nil {
+		return nil
+	}
+	return d.decodeNode(data)
+}
+
+func (d *jsonDecoder) decodeNode(data json.RawMessage) Node {
+	fields := d.object(data)
+	if fields == nil {
+		return nil
+	}
+	switch kind := d.kind(fields); kind {
+	case "LetStatement":
+		return LetStatement{Token: d.token(fields), Name: d.identifier(fields, "name"), Value: d.expression(fields, "value")}
+	case "AssignStatement":
+		return AssignStatement{Token: d.token(fields), Name: d.identifier(fields, "name"), Value: d.expression(fields, "value")}
+	case "ReturnStatement":
+		return ReturnStatement{Token: d.token(fields), ReturnValue: d.optionalExpression(fields, "returnValue")}
+	case "ExpressionStatement":
+		return ExpressionStatement{Token: d.token(fields), Expression: d.expression(fields, "expression")}
+	case "WhileStatement":
+		return WhileStatement{
+			Token:     d.token(fields),
+			Label:     d.string(fields, "label"),
+			Condition: d.expression(fields, "condition"),
+			Body:      d.block(fields, "body"),
+		}
+	case "BreakStatement":
+		return BreakStatement{Token: d.token(fields), Label: d.string(fields, "label")}
+	case "ContinueStatement":
+		return ContinueStatement{Token: d.token(fields), Label: d.string(fields, "label")}
+	case "BlockStatement":
+		return BlockStatement{
+			Token:      d.token(fields),
+			Statements: d.statements(fields, "statements"),
+			RBrace:     d.tokenField(fields, "rbrace"),
+		}
+	case "Identifier":
+		return Identifier{Token: d.token(fields), Value: d.string(fields, "value")}
+	case "IntegerLiteral":
+		var value int64
+		d.field(fields, "value", &value)
+		return IntegerLiteral{Token: d.token(fields), Value: value}
+	case "StringLiteral":
+		return StringLiteral{Token: d.token(fields), Value: d.string(fields, "value")}
+	case "Boolean":
+		var value bool
+		d.field(fields, "value", &value)
+		return Boolean{Token: d.token(fields), Value: value}
+	case "PrefixExpression":
+		return PrefixExpression{
+			Token:    d.token(fields),
+			Operator: d.string(fields, "operator"),
+			Right:    d.expression(fields, "right"),
+		}
+	case "InfixExpression":
+		return InfixExpression{
+			Token:    d.token(fields),
+			Left:     d.expression(fields, "left"),
+			Operator: d.string(fields, "operator"),
+			Right:    d.expression(fields, "right"),
+		}
+	case "IfExpression":
+		return IfExpression{
+			Token:       d.token(fields),
+			Condition:   d.expression(fields, "condition"),
+			Consequence: d.block(fields, "consequence"),
+			Alternative: d.optionalBlock(fields, "alternative"),
+		}
+	case "FunctionLiteral":
+		var paramsData []json.RawMessage
+		d.field(fields, "parameters", &paramsData)
+		var params []Identifier
+		for _, paramData := range paramsData {
+			param, ok := d.decodeNode(paramData).(Identifier)
+			if !ok && d.err == nil {
+				d.err = fmt.Errorf("FunctionLiteral node has a parameter which isn't an Identifier")
+			}
+			params = append(params, param)
+		}
+		return FunctionLiteral{Token: d.token(fields), Parameters: params, Body: d.block(fields, "body")}
+	case "CallExpression":
+		return CallExpression{
+			Token:     d.token(fields),
+			Function:  d.expression(fields, "function"),
+			Arguments: d.expressions(fields, "arguments"),
+		}
+	case "ArrayLiteral":
+		return ArrayLiteral{Token: d.token(fields), Elements: d.expressions(fields, "elements")}
+	case "IndexExpression":
+		return IndexExpression{Token: d.token(fields), Left: d.expression(fields, "left"), Index: d.expression(fields, "index")}
+	case "HashLiteral":
+		var pairsData []json.RawMessage
+		d.field(fields, "pairs", &pairsData)
+		var pairs []HashPair
+		for _, pairData := range pairsData {
+			pairFields := d.object(pairData)
+			pairs = append(pairs, HashPair{Key: d.expression(pairFields, "key"), Value: d.expression(pairFields, "value")})
+		}
+		return HashLiteral{Token: d.token(fields), Pairs: pairs}
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("unknown node kind %q", kind)
+		}
+		return nil
+	}
+}
+
+func (d *jsonDecoder) identifier(fields map[string]json.RawMessage, key string) Identifier {
+	ident, ok := d.node(fields, key).(Identifier)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("%s node has a %q field which isn't an Identifier", d.kind(fields), key)
+	}
+	return ident
+}
+
+func (d *jsonDecoder) expression(fields map[string]json.RawMessage, key string) Expression {
+	node := d.node(fields, key)
+	if node == nil {
+		d.nullFieldError(fields, key)
+		return nil
+	}
+	expr, ok := node.(Expression)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("%s node has a %q field which isn't an expression", d.kind(fields), key)
+	}
+	return expr
+}
+
+func (d *jsonDecoder) block(fields map[string]json.RawMessage, key string) *BlockStatement {
+	node := d.node(fields, key)
+	if node == nil {
+		d.nullFieldError(fields, key)
+		return nil
+	}
+	block, ok := node.(BlockStatement)
+	if !ok {
+		if d.err == nil {
+			d.err = fmt.Errorf("%s node has a %q field which isn't a BlockStatement", d.kind(fields), key)
+		}
+		return nil
+	}
+	return &block
+}
+
+// optionalExpression decodes the expression in the field with the given key like expression, except that the field is
+// allowed to be null, in which case nil is returned.
+func (d *jsonDecoder) optionalExpression(fields map[string]json.RawMessage, key string) Expression {
+	if d.isNull(fields, key) {
+		return nil
+	}
+	return d.expression(fields, key)
+}
+
+// optionalBlock decodes the block in the field with the given key like block, except that the field is allowed to be
+// null, in which case nil is returned.
+func (d *jsonDecoder) optionalBlock(fields map[string]json.RawMessage, key string) *BlockStatement {
+	if d.isNull(fields, key) {
+		return nil
+	}
+	return d.block(fields, key)
+}
+
+// isNull reports whether the field with the given key is present and null.
+func (d *jsonDecoder) isNull(fields map[string]json.RawMessage, key string) bool {
+	return d.err == nil && string(fields[key]) == "null"
+}
+
+// nullFieldError records an error for the field with the given key being null, where a node is required. Nothing is
+// recorded if an error has already been encountered, since that may be why no node was decoded.
+func (d *jsonDecoder) nullFieldError(fields map[string]json.RawMessage, key string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%s node has a null %q field", d.kind(fields), key)
+	}
+}
+
+func (d *jsonDecoder) statements(fields map[string]json.RawMessage, key string) []Statement {
+	var stmtsData []json.RawMessage
+	d.field(fields, key, &stmtsData)
+	var stmts []Statement
+	for _, stmtData := range stmtsData {
+		stmt, ok := d.decodeNode(stmtData).(Statement)
+		if !ok && d.err == nil {
+			d.err = fmt.Errorf("%s node has a %q element which isn't a statement", d.kind(fields), key)
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+func (d *jsonDecoder) expressions(fields map[string]json.RawMessage, key string) []Expression {
+	var exprsData []json.RawMessage
+	d.field(fields, key, &exprsData)
+	var exprs []Expression
+	for _, exprData := range exprsData {
+		expr, ok := d.decodeNode(exprData).(Expression)
+		if !ok && d.err == nil {
+			d.err = fmt.Errorf("%s node has a %q element which isn't an expression", d.kind(fields), key)
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs
+}
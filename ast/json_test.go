@@ -0,0 +1,159 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/marcuscaisey/monkey/ast"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+	}{
+		{name: "LetStatement", src: `let x = 5; let y = "foo";`},
+		{name: "AssignStatement", src: "let x = 1; x = x + 1;"},
+		{name: "ReturnStatement", src: "return 1; return;"},
+		{name: "WhileStatement", src: "while (x < 10) { x = x + 1; }"},
+		{name: "LabeledWhileStatement", src: "outer: while (true) { continue; break outer; }"},
+		{name: "PrefixAndInfixExpressions", src: "-a * b + !c == (d < e) && f || true"},
+		{name: "IfExpression", src: "if (x > 1) { x } else { y }; if (z) {}"},
+		{name: "FunctionLiteral", src: "let add = fn(x, y) { return x + y; }; fn() {}"},
+		{name: "CallExpression", src: "add(1, 2 * 3)(4)"},
+		{name: "ArrayAndIndexExpressions", src: "[1, 2, [3]][0][1]"},
+		{name: "HashLiteral", src: `{"a": 1, true: fn(x) { x }, 2: {}}`},
+		{name: "Empty", src: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			program := parse(t, tc.src)
+
+			data, err := ast.ToJSON(program)
+			if err != nil {
+				t.Fatalf("ToJSON() returned unexpected error for source %q: %s", tc.src, err)
+			}
+			got, err := ast.FromJSON(data)
+			if err != nil {
+				t.Fatalf("FromJSON(%s) returned unexpected error: %s", data, err)
+			}
+
+			if got.String() != program.String() {
+				t.Errorf("FromJSON(ToJSON(program)).String() = %q, want %q", got.String(), program.String())
+			}
+			if diff := cmp.Diff(program, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("FromJSON(ToJSON(program)) returned a different program\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	program := parse(t, "let x = 5;")
+	want := `{"kind":"Program","statements":[{"kind":"LetStatement",` +
+		`"name":{"kind":"Identifier","token":{"type":"IDENT","literal":"x","line":1,"column":5},"value":"x"},` +
+		`"token":{"type":"LET","literal":"let","line":1,"column":1},` +
+		`"value":{"kind":"IntegerLiteral","token":{"type":"INT","literal":"5","line":1,"column":9},"value":5}}]}`
+
+	data, err := ast.ToJSON(program)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, string(data)); diff != "" {
+		t.Fatalf("ToJSON() returned incorrect JSON\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestFromJSONReturnsError(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "InvalidJSON",
+			data: `{"kind":`,
+			want: "decoding AST from JSON: unexpected end of JSON input",
+		},
+		{
+			name: "NotProgram",
+			data: `{"kind":"Identifier","statements":[]}`,
+			want: "decoding AST from JSON: expected Program node, got Identifier",
+		},
+		{
+			name: "Null",
+			data: "null",
+			want: "decoding AST from JSON: expected Program node, got null",
+		},
+		{
+			name: "NonProgramNode",
+			data: `{"kind":"ExpressionStatement","token":{},"expression":null}`,
+			want: "decoding AST from JSON: expected Program node, got ExpressionStatement",
+		},
+		{
+			name: "MissingKind",
+			data: `{"statements":[]}`,
+			want: "decoding AST from JSON: expected Program node, got unknown",
+		},
+		{
+			name: "UnknownKind",
+			data: `{"kind":"Program","statements":[{"kind":"ForStatement"}]}`,
+			want: `decoding AST from JSON: unknown node kind "ForStatement"`,
+		},
+		{
+			name: "MissingField",
+			data: `{"kind":"Program","statements":[{"kind":"ExpressionStatement","token":{}}]}`,
+			want: `decoding AST from JSON: ExpressionStatement node is missing "expression" field`,
+		},
+		{
+			name: "NullExpression",
+			data: `{"kind":"Program","statements":[{"kind":"LetStatement","token":{},` +
+				`"name":{"kind":"Identifier","token":{},"value":"x"},"value":null}]}`,
+			want: `decoding AST from JSON: LetStatement node has a null "value" field`,
+		},
+		{
+			name: "NullBlock",
+			data: `{"kind":"Program","statements":[{"kind":"ExpressionStatement","token":{},"expression":` +
+				`{"kind":"IfExpression","token":{},"condition":{"kind":"Boolean","token":{},"value":true},` +
+				`"consequence":null,"alternative":null}}]}`,
+			want: `decoding AST from JSON: IfExpression node has a null "consequence" field`,
+		},
+		{
+			name: "MissingExpression",
+			data: `{"kind":"Program","statements":[{"kind":"ReturnStatement","token":{}}]}`,
+			want: `decoding AST from JSON: ReturnStatement node is missing "returnValue" field`,
+		},
+		{
+			name: "MissingBlock",
+			data: `{"kind":"Program","statements":[{"kind":"WhileStatement","token":{},"label":"",` +
+				`"condition":{"kind":"Boolean","token":{},"value":true}}]}`,
+			want: `decoding AST from JSON: WhileStatement node is missing "body" field`,
+		},
+		{
+			name: "NullElement",
+			data: `{"kind":"Program","statements":[{"kind":"ExpressionStatement","token":{},"expression":` +
+				`{"kind":"ArrayLiteral","token":{},"elements":[null]}}]}`,
+			want: `decoding AST from JSON: ArrayLiteral node has a "elements" element which isn't an expression`,
+		},
+		{
+			name: "WrongNodeType",
+			data: `{"kind":"Program","statements":[{"kind":"Identifier","token":{},"value":"x"}]}`,
+			want: `decoding AST from JSON: Program node has a "statements" element which isn't a statement`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ast.FromJSON([]byte(tc.data))
+			if err == nil {
+				t.Fatalf("FromJSON(%s) returned no error, want %q", tc.data, tc.want)
+			}
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("FromJSON(%s) returned error %q, want %q", tc.data, got, tc.want)
+			}
+		})
+	}
+}
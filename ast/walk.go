@@ -0,0 +1,94 @@
+package ast
+
+import "fmt"
+
+// Walk traverses the AST rooted at the given node in depth-first pre-order. visit is called with each node before its
+// children are walked. If visit returns false, then the children of the node are skipped. Nil child nodes, like the
+// ReturnValue of a bare return statement, aren't visited. Block statements are visited as the *BlockStatement which is
+// stored in the parent node.
+func Walk(node Node, visit func(Node) bool) {
+	if !visit(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		walkStatements(node.Statements, visit)
+	case Program:
+		walkStatements(node.Statements, visit)
+	case LetStatement:
+		Walk(node.Name, visit)
+		walkIfNotNil(node.Value, visit)
+	case AssignStatement:
+		Walk(node.Name, visit)
+		walkIfNotNil(node.Value, visit)
+	case ReturnStatement:
+		walkIfNotNil(node.ReturnValue, visit)
+	case ExpressionStatement:
+		walkIfNotNil(node.Expression, visit)
+	case WhileStatement:
+		walkIfNotNil(node.Condition, visit)
+		if node.Body != nil {
+			Walk(node.Body, visit)
+		}
+	case *BlockStatement:
+		walkStatements(node.Statements, visit)
+	case BlockStatement:
+		walkStatements(node.Statements, visit)
+	case BreakStatement, ContinueStatement, Identifier, IntegerLiteral, StringLiteral, Boolean:
+		// no children
+	case PrefixExpression:
+		walkIfNotNil(node.Right, visit)
+	case InfixExpression:
+		walkIfNotNil(node.Left, visit)
+		walkIfNotNil(node.Right, visit)
+	case IfExpression:
+		walkIfNotNil(node.Condition, visit)
+		if node.Consequence != nil {
+			Walk(node.Consequence, visit)
+		}
+		if node.Alternative != nil {
+			Walk(node.Alternative, visit)
+		}
+	case FunctionLiteral:
+		for _, param := range node.Parameters {
+			Walk(param, visit)
+		}
+		if node.Body != nil {
+			Walk(node.Body, visit)
+		}
+	case CallExpression:
+		walkIfNotNil(node.Function, visit)
+		walkExpressions(node.Arguments, visit)
+	case ArrayLiteral:
+		walkExpressions(node.Elements, visit)
+	case IndexExpression:
+		walkIfNotNil(node.Left, visit)
+		walkIfNotNil(node.Index, visit)
+	case HashLiteral:
+		for _, pair := range node.Pairs {
+			walkIfNotNil(pair.Key, visit)
+			walkIfNotNil(pair.Value, visit)
+		}
+	default:
+		panic(fmt.Sprintf("Walk: unexpected node type %T", node))
+	}
+}
+
+func walkIfNotNil(node Node, visit func(Node) bool) {
+	if node != nil {
+		Walk(node, visit)
+	}
+}
+
+func walkStatements(stmts []Statement, visit func(Node) bool) {
+	for _, stmt := range stmts {
+		walkIfNotNil(stmt, visit)
+	}
+}
+
+func walkExpressions(exprs []Expression, visit func(Node) bool) {
+	for _, expr := range exprs {
+		walkIfNotNil(expr, visit)
+	}
+}
@@ -0,0 +1,98 @@
+package ast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/parser"
+)
+
+func TestWalkVisitsEveryIdentifier(t *testing.T) {
+	testCases := []struct {
+		src  string
+		want int
+	}{
+		{src: "let x = y;", want: 2},
+		{src: "x = y + z;", want: 3},
+		{src: "return a; return;", want: 1},
+		{src: "-a * b[c]", want: 3},
+		{src: "if (a) { b } else { c; d }", want: 4},
+		{src: "while (a) { b = c; }", want: 3},
+		{src: "let add = fn(x, y) { return x + y; };", want: 5},
+		{src: "f(a, g(b))", want: 4},
+		{src: "[a, 1, b]", want: 2},
+		{src: `{a: b, "c": d}`, want: 3},
+		{src: "1 + 2", want: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			got := 0
+			ast.Walk(parse(t, tc.src), func(node ast.Node) bool {
+				if _, ok := node.(ast.Identifier); ok {
+					got++
+				}
+				return true
+			})
+			if got != tc.want {
+				t.Fatalf("Walk() visited %d identifiers in source %q, want %d", got, tc.src, tc.want)
+			}
+		})
+	}
+}
+
+func TestWalkVisitsInPreOrder(t *testing.T) {
+	want := []string{
+		"*ast.Program",
+		"ast.ExpressionStatement",
+		"ast.InfixExpression",
+		"ast.Identifier",
+		"ast.IfExpression",
+		"ast.Boolean",
+		"*ast.BlockStatement",
+		"ast.ExpressionStatement",
+		"ast.IntegerLiteral",
+	}
+
+	var got []string
+	ast.Walk(parse(t, "a + if (true) { 1 }"), func(node ast.Node) bool {
+		got = append(got, fmt.Sprintf("%T", node))
+		return true
+	})
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Walk() visited nodes in incorrect order\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestWalkSkipsChildrenWhenVisitReturnsFalse(t *testing.T) {
+	want := []string{"let f = fn(x) { x; };", "f(y);"}
+
+	var got []string
+	ast.Walk(parse(t, "let f = fn(x) { x }; f(y)"), func(node ast.Node) bool {
+		if _, ok := node.(ast.Statement); ok {
+			got = append(got, node.String())
+			return false
+		}
+		return true
+	})
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Walk() visited incorrect nodes\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+// parse parses the given source, failing the test if it can't be parsed.
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", src, errs)
+	}
+	return program
+}
@@ -0,0 +1,150 @@
+// Package code contains the bytecode instruction format which is shared by the [compiler] and [vm] packages.
+package code
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Instructions is a sequence of encoded instructions. Each instruction is an [Opcode] followed by its operands, whose
+// widths are determined by the [Definition] of the opcode.
+type Instructions []byte
+
+// String returns a human readable disassembly of the instructions with one instruction per line, like:
+//
+//	0000 OpConstant 0
+//	0003 OpConstant 1
+//	0006 OpAdd
+//
+// Each line starts with the offset of the instruction. An error line is written in place of an instruction whose opcode
+// is undefined, and in place of the last instruction if it's missing some of its operand bytes.
+func (ins Instructions) String() string {
+	var out bytes.Buffer
+	for i := 0; i < len(ins); {
+		def, err := Lookup(ins[i])
+		if err != nil {
+			fmt.Fprintf(&out, "error: %s\n", err)
+			i++
+			continue
+		}
+		if width, remaining := def.operandsWidth(), len(ins)-i-1; remaining < width {
+			fmt.Fprintf(&out, "error: %s needs %d operand bytes, got %d\n", def.Name, width, remaining)
+			break
+		}
+		operands, read := ReadOperands(def, ins[i+1:])
+		fmt.Fprintf(&out, "%04d %s\n", i, ins.formatInstruction(def, operands))
+		i += 1 + read
+	}
+	return out.String()
+}
+
+func (ins Instructions) formatInstruction(def *Definition, operands []int) string {
+	if len(operands) != len(def.OperandWidths) {
+		return fmt.Sprintf("error: operand len %d does not match defined %d", len(operands), len(def.OperandWidths))
+	}
+	switch len(operands) {
+	case 0:
+		return def.Name
+	case 1:
+		return fmt.Sprintf("%s %d", def.Name, operands[0])
+	}
+	return fmt.Sprintf("error: unhandled operand count for %s", def.Name)
+}
+
+// Opcode identifies the operation performed by an instruction.
+type Opcode byte
+
+const (
+	// OpConstant pushes the constant at the index given by its operand onto the stack.
+	OpConstant Opcode = iota
+	// OpAdd pops two values off the stack and pushes their sum.
+	OpAdd
+	// OpSub pops two values off the stack and pushes the first subtracted from the second.
+	OpSub
+	// OpMul pops two values off the stack and pushes their product.
+	OpMul
+	// OpDiv pops two values off the stack and pushes the second divided by the first.
+	OpDiv
+	// OpPop pops the value off the top of the stack.
+	OpPop
+)
+
+// Definition describes an [Opcode].
+type Definition struct {
+	// Name is the human readable name of the opcode.
+	Name string
+	// OperandWidths contains the number of bytes taken up by each of the opcode's operands.
+	OperandWidths []int
+}
+
+// operandsWidth returns the total number of bytes taken up by the operands of the opcode.
+func (def *Definition) operandsWidth() int {
+	width := 0
+	for _, operandWidth := range def.OperandWidths {
+		width += operandWidth
+	}
+	return width
+}
+
+var definitions = map[Opcode]*Definition{
+	OpConstant: {Name: "OpConstant", OperandWidths: []int{2}},
+	OpAdd:      {Name: "OpAdd", OperandWidths: []int{}},
+	OpSub:      {Name: "OpSub", OperandWidths: []int{}},
+	OpMul:      {Name: "OpMul", OperandWidths: []int{}},
+	OpDiv:      {Name: "OpDiv", OperandWidths: []int{}},
+	OpPop:      {Name: "OpPop", OperandWidths: []int{}},
+}
+
+// Lookup returns the definition of the given opcode.
+func Lookup(op byte) (*Definition, error) {
+	def, ok := definitions[Opcode(op)]
+	if !ok {
+		return nil, fmt.Errorf("opcode %d undefined", op)
+	}
+	return def, nil
+}
+
+// Make encodes an instruction with the given opcode and operands. Operands are encoded in big-endian order. An empty
+// instruction is returned if the opcode is undefined or the number of operands doesn't match its definition.
+func Make(op Opcode, operands ...int) []byte {
+	def, ok := definitions[op]
+	if !ok || len(operands) != len(def.OperandWidths) {
+		return []byte{}
+	}
+
+	instruction := make([]byte, 1+def.operandsWidth())
+	instruction[0] = byte(op)
+
+	offset := 1
+	for i, operand := range operands {
+		width := def.OperandWidths[i]
+		switch width {
+		case 2:
+			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		}
+		offset += width
+	}
+	return instruction
+}
+
+// ReadOperands decodes the operands of an instruction with the given definition from the start of ins, which should
+// not include the opcode. It returns the operands and the number of bytes that were read. It's the inverse of [Make].
+// ins must contain all of the operand bytes of the instruction.
+func ReadOperands(def *Definition, ins Instructions) ([]int, int) {
+	operands := make([]int, len(def.OperandWidths))
+	offset := 0
+	for i, width := range def.OperandWidths {
+		switch width {
+		case 2:
+			operands[i] = int(ReadUint16(ins[offset:]))
+		}
+		offset += width
+	}
+	return operands, offset
+}
+
+// ReadUint16 decodes a big-endian two byte operand from the start of ins.
+func ReadUint16(ins Instructions) uint16 {
+	return binary.BigEndian.Uint16(ins)
+}
@@ -0,0 +1,134 @@
+package code_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/code"
+)
+
+func TestMake(t *testing.T) {
+	testCases := []struct {
+		name     string
+		op       code.Opcode
+		operands []int
+		want     []byte
+	}{
+		{name: "TwoByteOperand", op: code.OpConstant, operands: []int{65534}, want: []byte{byte(code.OpConstant), 255, 254}},
+		{name: "NoOperands", op: code.OpAdd, want: []byte{byte(code.OpAdd)}},
+		{name: "UndefinedOpcode", op: code.Opcode(255), want: []byte{}},
+		{name: "TooManyOperands", op: code.OpConstant, operands: []int{1, 2}, want: []byte{}},
+		{name: "TooFewOperands", op: code.OpConstant, want: []byte{}},
+		{name: "UnexpectedOperand", op: code.OpAdd, operands: []int{1}, want: []byte{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := code.Make(tc.op, tc.operands...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Make(%d, %v) returned incorrect instruction\ndiff:\n--- want\n+++ got\n%s", tc.op, tc.operands, diff)
+			}
+		})
+	}
+}
+
+func TestReadOperands(t *testing.T) {
+	testCases := []struct {
+		name      string
+		op        code.Opcode
+		operands  []int
+		wantBytes int
+	}{
+		{name: "TwoByteOperand", op: code.OpConstant, operands: []int{65535}, wantBytes: 2},
+		{name: "NoOperands", op: code.OpPop, operands: []int{}, wantBytes: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			instruction := code.Make(tc.op, tc.operands...)
+			def, err := code.Lookup(byte(tc.op))
+			if err != nil {
+				t.Fatalf("Lookup(%d) returned unexpected error: %s", tc.op, err)
+			}
+
+			operands, read := code.ReadOperands(def, instruction[1:])
+			if read != tc.wantBytes {
+				t.Errorf("ReadOperands() read %d bytes, want %d", read, tc.wantBytes)
+			}
+			if diff := cmp.Diff(tc.operands, operands); diff != "" {
+				t.Errorf("ReadOperands() returned incorrect operands\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLookupReturnsErrorForUndefinedOpcode(t *testing.T) {
+	want := "opcode 255 undefined"
+	_, err := code.Lookup(255)
+	if err == nil {
+		t.Fatalf("Lookup(255) returned no error, want %q", want)
+	}
+	if got := err.Error(); got != want {
+		t.Fatalf("Lookup(255) returned error %q, want %q", got, want)
+	}
+}
+
+func TestInstructionsString(t *testing.T) {
+	var instructions code.Instructions
+	for _, instruction := range [][]byte{
+		code.Make(code.OpConstant, 1),
+		code.Make(code.OpConstant, 2),
+		code.Make(code.OpAdd),
+		code.Make(code.OpConstant, 65535),
+		code.Make(code.OpMul),
+		code.Make(code.OpPop),
+	} {
+		instructions = append(instructions, instruction...)
+	}
+
+	want := `0000 OpConstant 1
+0003 OpConstant 2
+0006 OpAdd
+0007 OpConstant 65535
+0010 OpMul
+0011 OpPop
+`
+	got := instructions.String()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("String() returned incorrect disassembly\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestInstructionsStringReportsMalformedInstructions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		instructions code.Instructions
+		want         string
+	}{
+		{
+			name:         "UndefinedOpcode",
+			instructions: code.Instructions{255, byte(code.OpPop)},
+			want:         "error: opcode 255 undefined\n0001 OpPop\n",
+		},
+		{
+			name:         "MissingOperandBytes",
+			instructions: code.Instructions{byte(code.OpConstant), 0},
+			want:         "error: OpConstant needs 2 operand bytes, got 1\n",
+		},
+		{
+			name:         "MissingOperands",
+			instructions: code.Instructions{byte(code.OpAdd), byte(code.OpConstant)},
+			want:         "0000 OpAdd\nerror: OpConstant needs 2 operand bytes, got 0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.instructions.String()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("String() returned incorrect disassembly\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package compiler contains a compiler which compiles the nodes from the [ast] package into the bytecode instructions
+// defined by the [code] package.
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/code"
+	"github.com/marcuscaisey/monkey/object"
+)
+
+// Compiler compiles an AST into [Bytecode].
+type Compiler struct {
+	instructions code.Instructions
+	constants    []object.Object
+}
+
+// New returns a Compiler which hasn't compiled anything yet.
+func New() *Compiler {
+	return &Compiler{
+		instructions: code.Instructions{},
+		constants:    []object.Object{},
+	}
+}
+
+// Bytecode is the result of compiling an AST.
+type Bytecode struct {
+	Instructions code.Instructions
+	// Constants contains the values which are referred to by the operands of OpConstant instructions.
+	Constants []object.Object
+}
+
+// Compile compiles the given node, appending to the instructions and constants which have already been compiled.
+func (c *Compiler) Compile(node ast.Node) error {
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, stmt := range node.Statements {
+			if err := c.Compile(stmt); err != nil {
+				return err
+			}
+		}
+	case ast.ExpressionStatement:
+		if err := c.Compile(node.Expression); err != nil {
+			return err
+		}
+		// the value of an expression statement isn't used so it's popped to leave the stack as it was
+		c.emit(code.OpPop)
+	case ast.InfixExpression:
+		if err := c.Compile(node.Left); err != nil {
+			return err
+		}
+		if err := c.Compile(node.Right); err != nil {
+			return err
+		}
+		switch node.Operator {
+		case "+":
+			c.emit(code.OpAdd)
+		case "-":
+			c.emit(code.OpSub)
+		case "*":
+			c.emit(code.OpMul)
+		case "/":
+			c.emit(code.OpDiv)
+		default:
+			return fmt.Errorf("unknown operator %s", node.Operator)
+		}
+	case ast.IntegerLiteral:
+		integer := &object.Integer{Value: node.Value}
+		c.emit(code.OpConstant, c.addConstant(integer))
+	default:
+		return fmt.Errorf("compiling %T is not supported", node)
+	}
+	return nil
+}
+
+// Bytecode returns the instructions and constants which have been compiled.
+func (c *Compiler) Bytecode() *Bytecode {
+	return &Bytecode{
+		Instructions: c.instructions,
+		Constants:    c.constants,
+	}
+}
+
+// addConstant adds the given value to the constants and returns its index.
+func (c *Compiler) addConstant(obj object.Object) int {
+	c.constants = append(c.constants, obj)
+	return len(c.constants) - 1
+}
+
+// emit appends an instruction with the given opcode and operands to the instructions and returns its position.
+func (c *Compiler) emit(op code.Opcode, operands ...int) int {
+	pos := len(c.instructions)
+	c.instructions = append(c.instructions, code.Make(op, operands...)...)
+	return pos
+}
@@ -0,0 +1,136 @@
+package compiler_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/code"
+	"github.com/marcuscaisey/monkey/compiler"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
+)
+
+func TestCompile(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want *compiler.Bytecode
+	}{
+		{
+			name: "Addition",
+			src:  "1 + 2",
+			want: &compiler.Bytecode{
+				Instructions: concat(
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpAdd),
+					code.Make(code.OpPop),
+				),
+				Constants: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}},
+			},
+		},
+		{
+			name: "Subtraction",
+			src:  "1 - 2",
+			want: &compiler.Bytecode{
+				Instructions: concat(
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpSub),
+					code.Make(code.OpPop),
+				),
+				Constants: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}},
+			},
+		},
+		{
+			name: "Precedence",
+			src:  "1 * 2 / 3",
+			want: &compiler.Bytecode{
+				Instructions: concat(
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpMul),
+					code.Make(code.OpConstant, 2),
+					code.Make(code.OpDiv),
+					code.Make(code.OpPop),
+				),
+				Constants: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				},
+			},
+		},
+		{
+			name: "ExpressionStatements",
+			src:  "1; 2",
+			want: &compiler.Bytecode{
+				Instructions: concat(
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpPop),
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpPop),
+				),
+				Constants: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := compiler.New()
+			if err := c.Compile(parse(t, tc.src)); err != nil {
+				t.Fatalf("Compile() returned unexpected error for source %q: %s", tc.src, err)
+			}
+			got := c.Bytecode()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Bytecode() returned incorrect bytecode for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestCompileReturnsErrorForUnsupportedNodes(t *testing.T) {
+	testCases := []struct {
+		src  string
+		want string
+	}{
+		{src: "let x = 1", want: "compiling ast.LetStatement is not supported"},
+		{src: "1 < 2", want: "unknown operator <"},
+		{src: "-1", want: "compiling ast.PrefixExpression is not supported"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			err := compiler.New().Compile(parse(t, tc.src))
+			if err == nil {
+				t.Fatalf("Compile() returned no error for source %q, want %q", tc.src, tc.want)
+			}
+			if got := err.Error(); got != tc.want {
+				t.Fatalf("Compile() returned error %q for source %q, want %q", got, tc.src, tc.want)
+			}
+		})
+	}
+}
+
+// parse parses the given source, failing the test if it can't be parsed.
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", src, errs)
+	}
+	return program
+}
+
+func concat(instructions ...[]byte) code.Instructions {
+	var result code.Instructions
+	for _, instruction := range instructions {
+		result = append(result, instruction...)
+	}
+	return result
+}
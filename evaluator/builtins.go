@@ -0,0 +1,474 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
+)
+
+// newBuiltins returns the functions which are provided by the evaluator, keyed by their names. Builtins can be shadowed
+// by bindings with the same name.
+func (e *Evaluator) newBuiltins() map[string]*object.Builtin {
+	return map[string]*object.Builtin{
+		"len":     {Fn: builtinLen},
+		"puts":    {Fn: e.builtinPuts},
+		"gets":    {Fn: e.builtinGets},
+		"input":   {Fn: e.builtinInput},
+		"first":   {Fn: builtinFirst},
+		"last":    {Fn: builtinLast},
+		"rest":    {Fn: builtinRest},
+		"push":    {Fn: builtinPush},
+		"type":    {Fn: builtinType},
+		"int":     {Fn: builtinInt},
+		"str":     {Fn: builtinStr},
+		"map":     {Fn: e.builtinMap},
+		"filter":  {Fn: e.builtinFilter},
+		"reduce":  {Fn: e.builtinReduce},
+		"keys":    {Fn: builtinKeys},
+		"values":  {Fn: builtinValues},
+		"delete":  {Fn: builtinDelete},
+		"flatten": {Fn: builtinFlatten},
+		"parse":   {Fn: e.builtinParse},
+	}
+}
+
+// BuiltinNames returns the sorted names of the builtin functions which are provided by the Evaluator.
+func (e *Evaluator) BuiltinNames() []string {
+	names := make([]string, 0, len(e.builtins))
+	for name := range e.builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinLen returns the number of characters in a string or the number of elements in an array.
+func builtinLen(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments: want 1, got %d", len(args))
+	}
+	switch arg := args[0].(type) {
+	case *object.String:
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *object.Array:
+		return &object.Integer{Value: int64(len(arg.Elements))}
+	default:
+		return newError("argument to `len` not supported, got %s", arg.Type())
+	}
+}
+
+// builtinPuts writes each of its arguments to the evaluator's output on a separate line and returns null.
+func (e *Evaluator) builtinPuts(args ...object.Object) object.Object {
+	for _, arg := range args {
+		if _, err := fmt.Fprintln(e.out, arg.Inspect()); err != nil {
+			return newError("puts: %s", err)
+		}
+	}
+	return nullObj
+}
+
+// builtinGets reads a line from the evaluator's input and returns it without its line ending, or null if the end of
+// the input has been reached.
+func (e *Evaluator) builtinGets(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments: want 0, got %d", len(args))
+	}
+	return e.readLine("gets")
+}
+
+// builtinInput writes an optional string prompt to the evaluator's output and then reads a line from its input like
+// gets.
+func (e *Evaluator) builtinInput(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("wrong number of arguments: want 0 or 1, got %d", len(args))
+	}
+	if len(args) == 1 {
+		prompt, ok := args[0].(*object.String)
+		if !ok {
+			return newError("argument to `input` must be %s, got %s", object.StringObj, args[0].Type())
+		}
+		if _, err := fmt.Fprint(e.out, prompt.Value); err != nil {
+			return newError("input: %s", err)
+		}
+	}
+	return e.readLine("input")
+}
+
+// readLine reads a line from the evaluator's input for the builtin with the given name. The line is returned without
+// its line ending, or null is returned if the end of the input has been reached. A final line which isn't terminated by
+// a line ending is still returned.
+func (e *Evaluator) readLine(name string) object.Object {
+	line, err := e.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return nullObj
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return newError("%s: %s", name, err)
+	}
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return &object.String{Value: line}
+}
+
+// builtinFirst returns the first element of an array, or null if the array is empty.
+func builtinFirst(args ...object.Object) object.Object {
+	array, err := arrayArgument("first", args, 1)
+	if err != nil {
+		return err
+	}
+	if len(array.Elements) == 0 {
+		return nullObj
+	}
+	return array.Elements[0]
+}
+
+// builtinLast returns the last element of an array, or null if the array is empty.
+func builtinLast(args ...object.Object) object.Object {
+	array, err := arrayArgument("last", args, 1)
+	if err != nil {
+		return err
+	}
+	if len(array.Elements) == 0 {
+		return nullObj
+	}
+	return array.Elements[len(array.Elements)-1]
+}
+
+// builtinRest returns a new array containing every element of an array apart from the first, or null if the array is
+// empty.
+func builtinRest(args ...object.Object) object.Object {
+	array, err := arrayArgument("rest", args, 1)
+	if err != nil {
+		return err
+	}
+	if len(array.Elements) == 0 {
+		return nullObj
+	}
+	elements := make([]object.Object, len(array.Elements)-1)
+	copy(elements, array.Elements[1:])
+	return &object.Array{Elements: elements}
+}
+
+// builtinPush returns a new array containing the elements of an array followed by a value. The original array isn't
+// modified.
+func builtinPush(args ...object.Object) object.Object {
+	array, err := arrayArgument("push", args, 2)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(array.Elements), len(array.Elements)+1)
+	copy(elements, array.Elements)
+	elements = append(elements, args[1])
+	return &object.Array{Elements: elements}
+}
+
+// builtinType returns the name of the type of a value, like "INTEGER" or "FUNCTION".
+func builtinType(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments: want 1, got %d", len(args))
+	}
+	return &object.String{Value: string(args[0].Type())}
+}
+
+// builtinInt converts a string containing a decimal integer, optionally preceded by a sign, to an integer. Integers
+// and big integers are returned unchanged.
+func builtinInt(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments: want 1, got %d", len(args))
+	}
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.BigInteger:
+		return arg
+	case *object.String:
+		value, err := strconv.ParseInt(arg.Value, 10, 64)
+		if err != nil {
+			return newError("argument to `int` is not a valid integer: %q", arg.Value)
+		}
+		return &object.Integer{Value: value}
+	default:
+		return newError("argument to `int` not supported, got %s", arg.Type())
+	}
+}
+
+// builtinStr converts a value to a string in the same format that puts uses to print it.
+func builtinStr(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments: want 1, got %d", len(args))
+	}
+	return &object.String{Value: args[0].Inspect()}
+}
+
+// builtinMap returns a new array containing the results of calling a function with each element of an array. If the
+// function returns an error, then mapping stops and the error is returned.
+func (e *Evaluator) builtinMap(args ...object.Object) object.Object {
+	array, err := arrayArgument("map", args, 2)
+	if err != nil {
+		return err
+	}
+	if err := checkFunctionArgument("map", args[1]); err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(array.Elements))
+	for i, element := range array.Elements {
+		result := e.applyFunction(args[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		elements[i] = result
+	}
+	return &object.Array{Elements: elements}
+}
+
+// builtinFilter returns a new array containing the elements of an array for which a function returns a truthy value.
+// If the function returns an error, then filtering stops and the error is returned.
+func (e *Evaluator) builtinFilter(args ...object.Object) object.Object {
+	array, err := arrayArgument("filter", args, 2)
+	if err != nil {
+		return err
+	}
+	if err := checkFunctionArgument("filter", args[1]); err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, element := range array.Elements {
+		result := e.applyFunction(args[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			elements = append(elements, element)
+		}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// builtinReduce folds an array into a single value by calling a function with the accumulated value and each element
+// in turn, starting with an initial value. The accumulated value after the last element is returned, which is the
+// initial value if the array is empty. If the function returns an error, then reducing stops and the error is
+// returned.
+func (e *Evaluator) builtinReduce(args ...object.Object) object.Object {
+	array, err := arrayArgument("reduce", args, 3)
+	if err != nil {
+		return err
+	}
+	if err := checkFunctionArgument("reduce", args[2]); err != nil {
+		return err
+	}
+	accumulated := args[1]
+	for _, element := range array.Elements {
+		accumulated = e.applyFunction(args[2], []object.Object{accumulated, element})
+		if isError(accumulated) {
+			return accumulated
+		}
+	}
+	return accumulated
+}
+
+// builtinKeys returns an array containing the keys of a hash in the order described by sortedPairs.
+func builtinKeys(args ...object.Object) object.Object {
+	hash, err := hashArgument("keys", args, 1)
+	if err != nil {
+		return err
+	}
+	pairs := sortedPairs(hash)
+	keys := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return &object.Array{Elements: keys}
+}
+
+// builtinValues returns an array containing the values of a hash in the order of their keys described by sortedPairs.
+func builtinValues(args ...object.Object) object.Object {
+	hash, err := hashArgument("values", args, 1)
+	if err != nil {
+		return err
+	}
+	pairs := sortedPairs(hash)
+	values := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		values[i] = pair.Value
+	}
+	return &object.Array{Elements: values}
+}
+
+// builtinDelete returns a new hash containing the pairs of a hash apart from the one with the given key. The original
+// hash isn't modified. If the key isn't present, then the new hash contains all of the original pairs.
+func builtinDelete(args ...object.Object) object.Object {
+	hash, err := hashArgument("delete", args, 2)
+	if err != nil {
+		return err
+	}
+	key, ok := args[1].(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", args[1].Type())
+	}
+	deletedKey := key.HashKey()
+	pairs := make(map[object.HashKey]object.HashPair, len(hash.Pairs))
+	for hashKey, pair := range hash.Pairs {
+		if hashKey != deletedKey {
+			pairs[hashKey] = pair
+		}
+	}
+	return &object.Hash{Pairs: pairs}
+}
+
+// builtinFlatten returns a new array containing the elements of an array with any nested arrays replaced by their
+// elements, recursively. An optional non-negative depth limits how many levels of nesting are flattened, so a depth of
+// 0 returns a copy of the array. An error is returned if an array which is being flattened contains itself, since it
+// could never be completely flattened.
+func builtinFlatten(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments: want 1 or 2, got %d", len(args))
+	}
+	array, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `flatten` must be %s, got %s", object.ArrayObj, args[0].Type())
+	}
+	depth := int64(-1)
+	if len(args) == 2 {
+		integer, ok := args[1].(*object.Integer)
+		if !ok {
+			return newError("depth passed to `flatten` must be %s, got %s", object.IntegerObj, args[1].Type())
+		}
+		if integer.Value < 0 {
+			return newError("depth passed to `flatten` must be non-negative, got %d", integer.Value)
+		}
+		depth = integer.Value
+	}
+	f := &flattener{elements: []object.Object{}, ancestors: map[*object.Array]bool{}}
+	if err := f.flatten(array, depth); err != nil {
+		return err
+	}
+	return &object.Array{Elements: f.elements}
+}
+
+// flattener accumulates the elements of a flattened array. ancestors contains the arrays which are currently being
+// flattened so that an array which contains itself can be detected.
+type flattener struct {
+	elements  []object.Object
+	ancestors map[*object.Array]bool
+}
+
+// flatten appends the elements of the array to the flattened elements, replacing nested arrays by their elements up
+// to the given depth, which is unlimited if it's negative.
+func (f *flattener) flatten(array *object.Array, depth int64) *object.Error {
+	f.ancestors[array] = true
+	defer delete(f.ancestors, array)
+	for _, element := range array.Elements {
+		nested, ok := element.(*object.Array)
+		if !ok || depth == 0 {
+			f.elements = append(f.elements, element)
+			continue
+		}
+		if f.ancestors[nested] {
+			return newError("argument to `flatten` contains itself")
+		}
+		if err := f.flatten(nested, depth-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builtinParse parses a string containing a single expression and returns the value that it evaluates to. The
+// expression is evaluated in a new environment, so it can't see or modify the bindings of the caller. An error is
+// returned if the string can't be parsed, if it contains anything other than a single expression, or if evaluating the
+// expression returns an error.
+func (e *Evaluator) builtinParse(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments: want 1, got %d", len(args))
+	}
+	src, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `parse` must be %s, got %s", object.StringObj, args[0].Type())
+	}
+	p := parser.New(lexer.New(src.Value))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		return newError("parse: %s", strings.Join(msgs, "; "))
+	}
+	if len(program.Statements) != 1 {
+		return newError("parse: want a single expression, got %d statements", len(program.Statements))
+	}
+	if _, ok := program.Statements[0].(ast.ExpressionStatement); !ok {
+		return newError("parse: want a single expression, got %s", program.Statements[0])
+	}
+	return e.Eval(program, object.NewEnvironment())
+}
+
+// sortedPairs returns the pairs of a hash in a deterministic order. Pairs are ordered by the type of their keys
+// (BOOLEAN, then INTEGER, then STRING) and then by their keys: false before true, integers in ascending order, and
+// strings in lexicographic order.
+func sortedPairs(hash *object.Hash) []object.HashPair {
+	pairs := make([]object.HashPair, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i].Key, pairs[j].Key
+		if a.Type() != b.Type() {
+			return a.Type() < b.Type()
+		}
+		switch a := a.(type) {
+		case *object.Boolean:
+			return !a.Value && b.(*object.Boolean).Value
+		case *object.Integer:
+			return a.Value < b.(*object.Integer).Value
+		case *object.String:
+			return a.Value < b.(*object.String).Value
+		default:
+			return a.Inspect() < b.Inspect()
+		}
+	})
+	return pairs
+}
+
+// checkFunctionArgument returns an error if the argument passed to the builtin with the given name can't be called.
+func checkFunctionArgument(name string, arg object.Object) *object.Error {
+	switch arg.Type() {
+	case object.FunctionObj, object.BuiltinObj:
+		return nil
+	default:
+		return newError("argument to `%s` must be %s, got %s", name, object.FunctionObj, arg.Type())
+	}
+}
+
+// arrayArgument checks that the builtin with the given name has been called with the given number of arguments and
+// that the first argument is an array, which is returned. Otherwise, an error is returned.
+func arrayArgument(name string, args []object.Object, wantArgs int) (*object.Array, *object.Error) {
+	if len(args) != wantArgs {
+		return nil, newError("wrong number of arguments: want %d, got %d", wantArgs, len(args))
+	}
+	array, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, newError("argument to `%s` must be %s, got %s", name, object.ArrayObj, args[0].Type())
+	}
+	return array, nil
+}
+
+// hashArgument checks that the builtin with the given name has been called with the given number of arguments and
+// that the first argument is a hash, which is returned. Otherwise, an error is returned.
+func hashArgument(name string, args []object.Object, wantArgs int) (*object.Hash, *object.Error) {
+	if len(args) != wantArgs {
+		return nil, newError("wrong number of arguments: want %d, got %d", wantArgs, len(args))
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return nil, newError("argument to `%s` must be %s, got %s", name, object.HashObj, args[0].Type())
+	}
+	return hash, nil
+}
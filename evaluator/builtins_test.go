@@ -0,0 +1,629 @@
+package evaluator_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/evaluator"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
+)
+
+func TestBuiltins(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want object.Object
+	}{
+		{name: "LenEmptyString", src: `len("")`, want: &object.Integer{Value: 0}},
+		{name: "LenString", src: `len("hello")`, want: &object.Integer{Value: 5}},
+		{name: "LenStringWithSpaces", src: `len("hello world")`, want: &object.Integer{Value: 11}},
+		{name: "LenMultibyteString", src: `len("héllo")`, want: &object.Integer{Value: 5}},
+		{name: "LenArray", src: "len([1, 2, 3])", want: &object.Integer{Value: 3}},
+		{name: "LenEmptyArray", src: "len([])", want: &object.Integer{Value: 0}},
+		{
+			name: "LenUnsupportedType",
+			src:  "len(5)",
+			want: &object.Error{Message: "argument to `len` not supported, got INTEGER"},
+		},
+		{
+			name: "LenTooManyArguments",
+			src:  `len("a", "b")`,
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 2"},
+		},
+		{
+			name: "LenNoArguments",
+			src:  "len()",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 0"},
+		},
+		{name: "First", src: "first([1, 2, 3])", want: &object.Integer{Value: 1}},
+		{name: "FirstEmpty", src: "first([])", want: &object.Null{}},
+		{
+			name: "FirstNonArray",
+			src:  "first(1)",
+			want: &object.Error{Message: "argument to `first` must be ARRAY, got INTEGER"},
+		},
+		{
+			name: "FirstTooManyArguments",
+			src:  "first([1], [2])",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 2"},
+		},
+		{name: "Last", src: "last([1, 2, 3])", want: &object.Integer{Value: 3}},
+		{name: "LastEmpty", src: "last([])", want: &object.Null{}},
+		{
+			name: "LastNonArray",
+			src:  `last("abc")`,
+			want: &object.Error{Message: "argument to `last` must be ARRAY, got STRING"},
+		},
+		{
+			name: "LastNoArguments",
+			src:  "last()",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 0"},
+		},
+		{
+			name: "Rest",
+			src:  "rest([1, 2, 3])",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 2}, &object.Integer{Value: 3}}},
+		},
+		{name: "RestSingleElement", src: "rest([1])", want: &object.Array{Elements: []object.Object{}}},
+		{name: "RestEmpty", src: "rest([])", want: &object.Null{}},
+		{
+			name: "RestDoesNotModifyArray",
+			src:  "let a = [1, 2, 3]; rest(a); a",
+			want: &object.Array{
+				Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}, &object.Integer{Value: 3}},
+			},
+		},
+		{
+			name: "RestNonArray",
+			src:  "rest(true)",
+			want: &object.Error{Message: "argument to `rest` must be ARRAY, got BOOLEAN"},
+		},
+		{name: "Push", src: "push([], 1)", want: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}},
+		{
+			name: "PushNonEmpty",
+			src:  `push([1], "two")`,
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.String{Value: "two"}}},
+		},
+		{
+			name: "PushDoesNotModifyArray",
+			src:  "let a = [1]; let b = push(a, 2); a",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+		},
+		{
+			name: "PushNonArray",
+			src:  "push(1, 1)",
+			want: &object.Error{Message: "argument to `push` must be ARRAY, got INTEGER"},
+		},
+		{
+			name: "PushTooFewArguments",
+			src:  "push([1])",
+			want: &object.Error{Message: "wrong number of arguments: want 2, got 1"},
+		},
+		{name: "TypeInteger", src: "type(1)", want: &object.String{Value: "INTEGER"}},
+		{name: "TypeString", src: `type("a")`, want: &object.String{Value: "STRING"}},
+		{name: "TypeBoolean", src: "type(true)", want: &object.String{Value: "BOOLEAN"}},
+		{name: "TypeNull", src: "type(fn() {}())", want: &object.String{Value: "NULL"}},
+		{name: "TypeArray", src: "type([])", want: &object.String{Value: "ARRAY"}},
+		{name: "TypeHash", src: "type({})", want: &object.String{Value: "HASH"}},
+		{name: "TypeFunction", src: "type(fn(x) { x })", want: &object.String{Value: "FUNCTION"}},
+		{name: "TypeBuiltin", src: "type(type)", want: &object.String{Value: "BUILTIN"}},
+		{name: "TypeOfType", src: "type(type(1))", want: &object.String{Value: "STRING"}},
+		{
+			name: "TypeNoArguments",
+			src:  "type()",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 0"},
+		},
+		{
+			name: "TypeTooManyArguments",
+			src:  "type(1, 2)",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 2"},
+		},
+		{name: "IntString", src: `int("42")`, want: &object.Integer{Value: 42}},
+		{name: "IntNegativeString", src: `int("-7")`, want: &object.Integer{Value: -7}},
+		{name: "IntInteger", src: "int(5)", want: &object.Integer{Value: 5}},
+		{
+			name: "IntNonNumericString",
+			src:  `int("x")`,
+			want: &object.Error{Message: "argument to `int` is not a valid integer: \"x\""},
+		},
+		{
+			name: "IntEmptyString",
+			src:  `int("")`,
+			want: &object.Error{Message: "argument to `int` is not a valid integer: \"\""},
+		},
+		{
+			name: "IntOutOfRange",
+			src:  `int("9223372036854775808")`,
+			want: &object.Error{Message: "argument to `int` is not a valid integer: \"9223372036854775808\""},
+		},
+		{
+			name: "IntUnsupportedType",
+			src:  "int(true)",
+			want: &object.Error{Message: "argument to `int` not supported, got BOOLEAN"},
+		},
+		{name: "IntNoArguments", src: "int()", want: &object.Error{Message: "wrong number of arguments: want 1, got 0"}},
+		{name: "StrInteger", src: "str(42)", want: &object.String{Value: "42"}},
+		{name: "StrBoolean", src: "str(true)", want: &object.String{Value: "true"}},
+		{name: "StrString", src: `str("a")`, want: &object.String{Value: "a"}},
+		{name: "StrArray", src: `str([1, "a"])`, want: &object.String{Value: "[1, a]"}},
+		{name: "StrRoundTrip", src: `int(str(-12)) + 1`, want: &object.Integer{Value: -11}},
+		{
+			name: "StrTooManyArguments",
+			src:  "str(1, 2)",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 2"},
+		},
+		{
+			name: "Map",
+			src:  "map([1, 2, 3], fn(x) { x * 2 })",
+			want: &object.Array{
+				Elements: []object.Object{&object.Integer{Value: 2}, &object.Integer{Value: 4}, &object.Integer{Value: 6}},
+			},
+		},
+		{name: "MapEmpty", src: "map([], fn(x) { x * 2 })", want: &object.Array{Elements: []object.Object{}}},
+		{
+			name: "MapBuiltin",
+			src:  `map(["a", "bc"], len)`,
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}}},
+		},
+		{
+			name: "MapDoesNotModifyArray",
+			src:  "let a = [1]; map(a, fn(x) { x + 1 }); a",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+		},
+		{
+			name: "MapCallbackError",
+			src:  "map([1, true, 3], fn(x) { x * 2 })",
+			want: &object.Error{Message: "type mismatch: BOOLEAN * INTEGER"},
+		},
+		{
+			name: "MapCallbackWrongNumberOfArguments",
+			src:  "map([1], fn(x, y) { x })",
+			want: &object.Error{Message: "wrong number of arguments: want 2, got 1"},
+		},
+		{
+			name: "MapNonArray",
+			src:  "map(1, fn(x) { x })",
+			want: &object.Error{Message: "argument to `map` must be ARRAY, got INTEGER"},
+		},
+		{
+			name: "MapNonFunction",
+			src:  "map([1], 1)",
+			want: &object.Error{Message: "argument to `map` must be FUNCTION, got INTEGER"},
+		},
+		{
+			name: "Filter",
+			src:  "filter([1, 2, 3, 4], fn(x) { x % 2 == 0 })",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 2}, &object.Integer{Value: 4}}},
+		},
+		{
+			name: "FilterTruthiness",
+			src:  "filter([0, false, 1, fn() {}()], fn(x) { x })",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 0}, &object.Integer{Value: 1}}},
+		},
+		{name: "FilterNoneMatch", src: "filter([1, 3], fn(x) { x % 2 == 0 })", want: &object.Array{Elements: []object.Object{}}},
+		{
+			name: "FilterCallbackError",
+			src:  "filter([1, 2], fn(x) { y })",
+			want: &object.Error{Message: "identifier not found: y"},
+		},
+		{
+			name: "FilterNonFunction",
+			src:  `filter([1], "f")`,
+			want: &object.Error{Message: "argument to `filter` must be FUNCTION, got STRING"},
+		},
+		{
+			name: "FilterTooFewArguments",
+			src:  "filter([1])",
+			want: &object.Error{Message: "wrong number of arguments: want 2, got 1"},
+		},
+		{name: "ReduceSum", src: "reduce([1, 2, 3, 4], 0, fn(acc, x) { acc + x })", want: &object.Integer{Value: 10}},
+		{
+			name: "ReduceConcatenate",
+			src:  `reduce(["a", "b", "c"], ">", fn(acc, s) { acc + s })`,
+			want: &object.String{Value: ">abc"},
+		},
+		{name: "ReduceEmpty", src: `reduce([], "initial", fn(acc, x) { x })`, want: &object.String{Value: "initial"}},
+		{
+			name: "ReduceIntoArray",
+			src:  "reduce([1, 2], [], fn(acc, x) { push(acc, x * 10) })",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 10}, &object.Integer{Value: 20}}},
+		},
+		{
+			name: "ReduceCallbackError",
+			src:  `reduce([1, "a"], 0, fn(acc, x) { acc + x })`,
+			want: &object.Error{Message: "type mismatch: INTEGER + STRING"},
+		},
+		{
+			name: "ReduceNonFunction",
+			src:  "reduce([1], 0, [])",
+			want: &object.Error{Message: "argument to `reduce` must be FUNCTION, got ARRAY"},
+		},
+		{
+			name: "ReduceNonArray",
+			src:  "reduce(1, 0, fn(acc, x) { acc })",
+			want: &object.Error{Message: "argument to `reduce` must be ARRAY, got INTEGER"},
+		},
+		{
+			name: "ReduceTooFewArguments",
+			src:  "reduce([1], fn(acc, x) { acc })",
+			want: &object.Error{Message: "wrong number of arguments: want 3, got 2"},
+		},
+		{
+			name: "Keys",
+			src:  `keys({"b": 1, 10: 2, "a": 3, true: 4, -1: 5, false: 6})`,
+			want: &object.Array{Elements: []object.Object{
+				&object.Boolean{Value: false},
+				&object.Boolean{Value: true},
+				&object.Integer{Value: -1},
+				&object.Integer{Value: 10},
+				&object.String{Value: "a"},
+				&object.String{Value: "b"},
+			}},
+		},
+		{name: "KeysEmpty", src: "keys({})", want: &object.Array{Elements: []object.Object{}}},
+		{
+			name: "KeysNonHash",
+			src:  "keys([1])",
+			want: &object.Error{Message: "argument to `keys` must be HASH, got ARRAY"},
+		},
+		{
+			name: "Values",
+			src:  `values({"b": 1, 2: "x", "a": 3})`,
+			want: &object.Array{Elements: []object.Object{
+				&object.String{Value: "x"},
+				&object.Integer{Value: 3},
+				&object.Integer{Value: 1},
+			}},
+		},
+		{
+			name: "ValuesTooManyArguments",
+			src:  "values({}, {})",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 2"},
+		},
+		{
+			name: "DeletePresentKey",
+			src:  `delete({"a": 1, "b": 2}, "a")`,
+			want: &object.Hash{Pairs: map[object.HashKey]object.HashPair{
+				(&object.String{Value: "b"}).HashKey(): {Key: &object.String{Value: "b"}, Value: &object.Integer{Value: 2}},
+			}},
+		},
+		{
+			name: "DeleteAbsentKey",
+			src:  `delete({"a": 1}, 1)`,
+			want: &object.Hash{Pairs: map[object.HashKey]object.HashPair{
+				(&object.String{Value: "a"}).HashKey(): {Key: &object.String{Value: "a"}, Value: &object.Integer{Value: 1}},
+			}},
+		},
+		{
+			name: "DeleteDoesNotModifyHash",
+			src:  `let h = {"a": 1}; delete(h, "a"); h["a"]`,
+			want: &object.Integer{Value: 1},
+		},
+		{
+			name: "DeleteUnhashableKey",
+			src:  `delete({"a": 1}, [])`,
+			want: &object.Error{Message: "unusable as hash key: ARRAY"},
+		},
+		{
+			name: "DeleteNonHash",
+			src:  `delete("a", "a")`,
+			want: &object.Error{Message: "argument to `delete` must be HASH, got STRING"},
+		},
+		{
+			name: "FlattenNested",
+			src:  "flatten([1, [2, [3, [4]]], 5])",
+			want: integerArray(1, 2, 3, 4, 5),
+		},
+		{name: "FlattenFlat", src: "flatten([1, 2])", want: integerArray(1, 2)},
+		{name: "FlattenEmpty", src: "flatten([])", want: integerArray()},
+		{name: "FlattenEmptyNested", src: "flatten([[], [[]], 1])", want: integerArray(1)},
+		{
+			name: "FlattenWithDepth",
+			src:  "flatten([1, [2, [3, [4]]]], 1)",
+			want: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 1},
+				&object.Integer{Value: 2},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 3},
+					&object.Array{Elements: []object.Object{&object.Integer{Value: 4}}},
+				}},
+			}},
+		},
+		{
+			name: "FlattenWithZeroDepth",
+			src:  "flatten([1, [2]], 0)",
+			want: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 1},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 2}}},
+			}},
+		},
+		{name: "FlattenWithDepthGreaterThanNesting", src: "flatten([1, [2]], 5)", want: integerArray(1, 2)},
+		{name: "FlattenDoesNotModifyArray", src: "let a = [1, [2]]; flatten(a); len(a)", want: &object.Integer{Value: 2}},
+		{
+			name: "FlattenNonArray",
+			src:  `flatten("abc")`,
+			want: &object.Error{Message: "argument to `flatten` must be ARRAY, got STRING"},
+		},
+		{
+			name: "FlattenNegativeDepth",
+			src:  "flatten([1], -1)",
+			want: &object.Error{Message: "depth passed to `flatten` must be non-negative, got -1"},
+		},
+		{
+			name: "FlattenNonIntegerDepth",
+			src:  `flatten([1], "1")`,
+			want: &object.Error{Message: "depth passed to `flatten` must be INTEGER, got STRING"},
+		},
+		{
+			name: "FlattenNoArguments",
+			src:  "flatten()",
+			want: &object.Error{Message: "wrong number of arguments: want 1 or 2, got 0"},
+		},
+		{
+			name: "FlattenTooManyArguments",
+			src:  "flatten([1], 1, 1)",
+			want: &object.Error{Message: "wrong number of arguments: want 1 or 2, got 3"},
+		},
+		{name: "ParseArray", src: `parse("[1, 2, 3]")`, want: integerArray(1, 2, 3)},
+		{
+			name: "ParseHash",
+			src:  `parse("{\"a\": 1}")["a"]`,
+			want: &object.Integer{Value: 1},
+		},
+		{name: "ParseEvaluatesExpression", src: `parse("1 + 2 * 3")`, want: &object.Integer{Value: 7}},
+		{name: "ParseFunction", src: `parse("fn(x) { x * 2 }")(4)`, want: &object.Integer{Value: 8}},
+		{name: "ParseReturnInBlock", src: `parse("if (true) { return 1; }")`, want: &object.Integer{Value: 1}},
+		{
+			name: "ParseIsolatedEnvironment",
+			src:  `let x = 1; parse("x")`,
+			want: &object.Error{Message: "identifier not found: x"},
+		},
+		{
+			name: "ParseError",
+			src:  `parse("[1, 2")`,
+			want: &object.Error{Message: "parse: 1:6: expected R_BRACKET, got EOF"},
+		},
+		{
+			name: "ParseMultipleErrors",
+			src:  `parse("let = 1; let y 2")`,
+			want: &object.Error{
+				Message: `parse: 1:5: expected IDENT, got ASSIGN("="); 1:16: expected ASSIGN, got INT("2")`,
+			},
+		},
+		{
+			name: "ParseEvaluationError",
+			src:  `parse("1 + true")`,
+			want: &object.Error{Message: "type mismatch: INTEGER + BOOLEAN"},
+		},
+		{
+			name: "ParseMultipleExpressions",
+			src:  `parse("1; 2")`,
+			want: &object.Error{Message: "parse: want a single expression, got 2 statements"},
+		},
+		{
+			name: "ParseEmptyString",
+			src:  `parse("")`,
+			want: &object.Error{Message: "parse: want a single expression, got 0 statements"},
+		},
+		{
+			name: "ParseStatement",
+			src:  `parse("let x = 1")`,
+			want: &object.Error{Message: "parse: want a single expression, got let x = 1;"},
+		},
+		{
+			name: "ParseNonString",
+			src:  "parse(1)",
+			want: &object.Error{Message: "argument to `parse` must be STRING, got INTEGER"},
+		},
+		{
+			name: "ParseNoArguments",
+			src:  "parse()",
+			want: &object.Error{Message: "wrong number of arguments: want 1, got 0"},
+		},
+		{
+			name: "RecursiveMap",
+			src: `let map = fn(arr, f) {
+  let iter = fn(arr, accumulated) {
+    if (len(arr) == 0) {
+      accumulated
+    } else {
+      iter(rest(arr), push(accumulated, f(first(arr))));
+    }
+  };
+  iter(arr, []);
+};
+map([1, 2, 3], fn(x) { x * 2 })`,
+			want: &object.Array{
+				Elements: []object.Object{&object.Integer{Value: 2}, &object.Integer{Value: 4}, &object.Integer{Value: 6}},
+			},
+		},
+		{name: "ShadowedBuiltin", src: "let len = fn(x) { 42 }; len([])", want: &object.Integer{Value: 42}},
+		{name: "BuiltinAsValue", src: `let f = len; f("abc")`, want: &object.Integer{Value: 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := eval(t, tc.src)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestFlattenSelfReferentialArray(t *testing.T) {
+	src := "flatten(a)"
+	a := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}
+	a.Elements = append(a.Elements, &object.Array{Elements: []object.Object{a}})
+	env := object.NewEnvironment()
+	env.Set("a", a)
+	want := &object.Error{Message: "argument to `flatten` contains itself"}
+
+	got := evaluator.Eval(parse(t, src), env)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestPuts(t *testing.T) {
+	testCases := []struct {
+		name    string
+		src     string
+		wantOut string
+	}{
+		{name: "MultipleArguments", src: `puts("hi", 5)`, wantOut: "hi\n5\n"},
+		{name: "NoArguments", src: "puts()", wantOut: ""},
+		{name: "Inspect", src: `puts([1, "two"], true, {"a": 1})`, wantOut: "[1, two]\ntrue\n{a: 1}\n"},
+		{name: "MultipleCalls", src: `puts("a"); puts("b")`, wantOut: "a\nb\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.src))
+			program := p.ParseProgram()
+			if errs := p.Errors(); len(errs) > 0 {
+				t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", tc.src, errs)
+			}
+			out := &bytes.Buffer{}
+
+			got := evaluator.New(out, strings.NewReader("")).Eval(program, object.NewEnvironment())
+
+			if diff := cmp.Diff(&object.Null{}, got); diff != "" {
+				t.Errorf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+			if diff := cmp.Diff(tc.wantOut, out.String()); diff != "" {
+				t.Errorf("Eval() wrote incorrect output for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestGets(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		in   string
+		want object.Object
+	}{
+		{name: "Line", src: "gets()", in: "hello\nworld\n", want: &object.String{Value: "hello"}},
+		{name: "SuccessiveLines", src: "gets(); gets()", in: "hello\nworld\n", want: &object.String{Value: "world"}},
+		{name: "EmptyLine", src: "gets()", in: "\n", want: &object.String{Value: ""}},
+		{name: "CRLF", src: "gets()", in: "hello\r\n", want: &object.String{Value: "hello"}},
+		{name: "UnterminatedLine", src: "gets()", in: "hello", want: &object.String{Value: "hello"}},
+		{name: "EOF", src: "gets()", in: "", want: &object.Null{}},
+		{name: "AfterLastLine", src: "gets(); gets()", in: "hello\n", want: &object.Null{}},
+		{
+			name: "TooManyArguments",
+			src:  `gets("a")`,
+			in:   "hello\n",
+			want: &object.Error{Message: "wrong number of arguments: want 0, got 1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			program := parse(t, tc.src)
+
+			got := evaluator.New(&bytes.Buffer{}, strings.NewReader(tc.in)).Eval(program, object.NewEnvironment())
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Eval() returned incorrect object for source %q with input %q\ndiff:\n--- want\n+++ got\n%s", tc.src, tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestInput(t *testing.T) {
+	testCases := []struct {
+		name    string
+		src     string
+		in      string
+		want    object.Object
+		wantOut string
+	}{
+		{
+			name:    "Prompt",
+			src:     `input("name: ")`,
+			in:      "Monkey\n",
+			want:    &object.String{Value: "Monkey"},
+			wantOut: "name: ",
+		},
+		{
+			name:    "NoPrompt",
+			src:     "input()",
+			in:      "Monkey\n",
+			want:    &object.String{Value: "Monkey"},
+			wantOut: "",
+		},
+		{
+			name:    "SuccessiveCalls",
+			src:     `input("a: ") + input("b: ")`,
+			in:      "1\n2\n",
+			want:    &object.String{Value: "12"},
+			wantOut: "a: b: ",
+		},
+		{
+			name:    "EOF",
+			src:     `input("name: ")`,
+			in:      "",
+			want:    &object.Null{},
+			wantOut: "name: ",
+		},
+		{
+			name: "NonStringPrompt",
+			src:  "input(5)",
+			in:   "Monkey\n",
+			want: &object.Error{Message: "argument to `input` must be STRING, got INTEGER"},
+		},
+		{
+			name: "TooManyArguments",
+			src:  `input("a", "b")`,
+			in:   "Monkey\n",
+			want: &object.Error{Message: "wrong number of arguments: want 0 or 1, got 2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			program := parse(t, tc.src)
+			out := &bytes.Buffer{}
+
+			got := evaluator.New(out, strings.NewReader(tc.in)).Eval(program, object.NewEnvironment())
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Eval() returned incorrect object for source %q with input %q\ndiff:\n--- want\n+++ got\n%s", tc.src, tc.in, diff)
+			}
+			if diff := cmp.Diff(tc.wantOut, out.String()); diff != "" {
+				t.Errorf("Eval() wrote incorrect output for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestBuiltinNames(t *testing.T) {
+	want := []string{
+		"delete", "filter", "first", "flatten", "gets", "input", "int", "keys", "last", "len", "map", "parse", "push",
+		"puts", "reduce", "rest", "str", "type", "values",
+	}
+
+	got := evaluator.New(&bytes.Buffer{}, strings.NewReader("")).BuiltinNames()
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("BuiltinNames() returned incorrect names\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+// integerArray returns an array containing integers with the given values.
+func integerArray(values ...int64) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, value := range values {
+		elements[i] = &object.Integer{Value: value}
+	}
+	return &object.Array{Elements: elements}
+}
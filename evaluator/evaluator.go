@@ -0,0 +1,627 @@
+// Package evaluator contains a tree-walking evaluator for the Monkey language which evaluates the nodes from the [ast]
+// package into the values from the [object] package.
+package evaluator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/object"
+)
+
+// There's only ever one true, false, and null value so that they can be compared by identity and don't need to be
+// allocated every time that they're evaluated.
+var (
+	trueObj  = &object.Boolean{Value: true}
+	falseObj = &object.Boolean{Value: false}
+	nullObj  = &object.Null{}
+)
+
+// stdin is shared by every Evaluator which reads from [os.Stdin] so that input which has been buffered by one of them
+// isn't lost when reading from another.
+var stdin = bufio.NewReader(os.Stdin)
+
+// Evaluator evaluates the nodes of an AST. The zero value isn't usable, so Evaluators should be created with [New].
+type Evaluator struct {
+	out         io.Writer
+	in          *bufio.Reader
+	builtins    map[string]*object.Builtin
+	bigIntegers bool
+}
+
+// Option configures an Evaluator which is created by [New].
+type Option func(*Evaluator)
+
+// WithBigIntegers returns an Option which makes integer arithmetic promote its result to an [object.BigInteger] when it
+// would overflow an [object.Integer], rather than wrapping around. Arithmetic and comparisons between integers and big
+// integers promote the integer to a big integer. Results which fit in an integer are always returned as one, so that
+// they can still be used wherever an integer is expected, like as an array index.
+func WithBigIntegers() Option {
+	return func(e *Evaluator) {
+		e.bigIntegers = true
+	}
+}
+
+// New returns an Evaluator which writes the output of builtins like puts to the given writer and reads the input of
+// builtins like gets from the given reader. If out is nil, then output is written to [os.Stdout]. If in is nil, then
+// input is read from [os.Stdin]. Input is buffered, so in shouldn't be read from by anything else whilst the Evaluator
+// is in use. The exception is [os.Stdin], which is buffered once and shared by every Evaluator which reads from it.
+// The given options are applied to the Evaluator in order.
+func New(out io.Writer, in io.Reader, opts ...Option) *Evaluator {
+	if out == nil {
+		out = os.Stdout
+	}
+	if in == nil || in == os.Stdin {
+		in = stdin
+	}
+	e := &Evaluator{out: out, in: bufio.NewReader(in)}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.builtins = e.newBuiltins()
+	return e
+}
+
+// Eval evaluates the given node in the given environment using an Evaluator which writes its output to [os.Stdout] and
+// reads its input from [os.Stdin]. Input from [os.Stdin] is buffered in a reader shared by every call, so reading it
+// directly between calls may miss input which has already been buffered. See [Evaluator.Eval] for details.
+func Eval(node ast.Node, env *object.Environment) object.Object {
+	return New(os.Stdout, os.Stdin).Eval(node, env)
+}
+
+// Eval evaluates the given node in the given environment and returns the value that it evaluates to. If an error occurs
+// whilst evaluating the node, like applying an operator to operands of the wrong type, then evaluation stops and an
+// [object.Error] is returned.
+func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
+	switch node := node.(type) {
+	case *ast.Program:
+		return e.evalProgram(node, env)
+	case ast.LetStatement:
+		val := e.Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		env.Set(node.Name.Value, val)
+		return nullObj
+	case ast.AssignStatement:
+		val := e.Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		if !env.Assign(node.Name.Value, val) {
+			return newError("identifier not found: %s", node.Name.Value)
+		}
+		return nullObj
+	case ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			return &object.ReturnValue{Value: nullObj}
+		}
+		val := e.Eval(node.ReturnValue, env)
+		if isError(val) {
+			return val
+		}
+		return &object.ReturnValue{Value: val}
+	case ast.ExpressionStatement:
+		return e.Eval(node.Expression, env)
+	case ast.WhileStatement:
+		return e.evalWhileStatement(node, env)
+	case ast.BreakStatement:
+		return &object.Break{Label: node.Label}
+	case ast.ContinueStatement:
+		return &object.Continue{Label: node.Label}
+	case *ast.BlockStatement:
+		return e.evalBlockStatement(node, env)
+	case ast.Identifier:
+		return e.evalIdentifier(node, env)
+	case ast.IntegerLiteral:
+		return &object.Integer{Value: node.Value}
+	case ast.StringLiteral:
+		return &object.String{Value: node.Value}
+	case ast.Boolean:
+		return nativeBoolToBooleanObject(node.Value)
+	case ast.PrefixExpression:
+		right := e.Eval(node.Right, env)
+		if isError(right) {
+			return right
+		}
+		return e.evalPrefixExpression(node.Operator, right)
+	case ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return e.evalLogicalExpression(node, env)
+		}
+		left := e.Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		right := e.Eval(node.Right, env)
+		if isError(right) {
+			return right
+		}
+		return e.evalInfixExpression(node.Operator, left, right)
+	case ast.ArrayLiteral:
+		elements := e.evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &object.Array{Elements: elements}
+	case ast.HashLiteral:
+		return e.evalHashLiteral(node, env)
+	case ast.IndexExpression:
+		left := e.Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := e.Eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case ast.IfExpression:
+		return e.evalIfExpression(node, env)
+	case ast.FunctionLiteral:
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+	case ast.CallExpression:
+		function := e.Eval(node.Function, env)
+		if isError(function) {
+			return function
+		}
+		args := e.evalExpressions(node.Arguments, env)
+		if len(args) == 1 && isError(args[0]) {
+			return args[0]
+		}
+		return e.applyFunction(function, args)
+	default:
+		return nullObj
+	}
+}
+
+// evalProgram evaluates the statements of the program in order and returns the value of the last one, or null if there
+// aren't any. Evaluation stops at the first return statement which is evaluated, whose value is returned instead, or at
+// the first error. A break or continue statement which isn't inside a matching loop evaluates to an error.
+func (e *Evaluator) evalProgram(program *ast.Program, env *object.Environment) object.Object {
+	var result object.Object = nullObj
+	for _, stmt := range program.Statements {
+		result = e.Eval(stmt, env)
+		switch result := result.(type) {
+		case *object.ReturnValue:
+			return result.Value
+		case *object.Error:
+			return result
+		case *object.Break, *object.Continue:
+			return unmatchedLoopControlError(result)
+		}
+	}
+	return result
+}
+
+// evalBlockStatement evaluates the statements of the block in order and returns the value of the last one, or null if
+// there aren't any. Evaluation stops at the first return, break, or continue statement which is evaluated, whose
+// [object.ReturnValue], [object.Break], or [object.Continue] is returned so that it also stops the evaluation of any
+// enclosing blocks, or at the first error.
+func (e *Evaluator) evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+	var result object.Object = nullObj
+	for _, stmt := range block.Statements {
+		result = e.Eval(stmt, env)
+		switch result.Type() {
+		case object.ReturnValueObj, object.ErrorObj, object.BreakObj, object.ContinueObj:
+			return result
+		}
+	}
+	return result
+}
+
+// evalWhileStatement evaluates the body of the loop for as long as the condition is truthy. The loop evaluates to null
+// rather than the value of the last evaluation of its body, since the body may not be evaluated at all. A return
+// statement in the body stops the loop and its [object.ReturnValue] is returned so that it also stops the evaluation
+// of the enclosing function. Evaluation also stops if the condition or body evaluates to an error.
+//
+// A break statement in the body stops the loop and a continue statement skips the rest of the body, if the statement
+// has no label or its label is the label of the loop. Otherwise, its [object.Break] or [object.Continue] is returned so
+// that it stops the enclosing loops until it reaches the one with the matching label.
+func (e *Evaluator) evalWhileStatement(stmt ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := e.Eval(stmt.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return nullObj
+		}
+		result := e.evalBlockStatement(stmt.Body, env)
+		switch result := result.(type) {
+		case *object.ReturnValue, *object.Error:
+			return result
+		case *object.Break:
+			if result.Label != "" && result.Label != stmt.Label {
+				return result
+			}
+			return nullObj
+		case *object.Continue:
+			if result.Label != "" && result.Label != stmt.Label {
+				return result
+			}
+		}
+	}
+}
+
+// evalLogicalExpression evaluates left && right or left || right to true or false based on the truthiness of the
+// operands. The right operand is only evaluated if the left one doesn't already determine the result, so false && x
+// evaluates to false and true || x evaluates to true without evaluating x.
+func (e *Evaluator) evalLogicalExpression(node ast.InfixExpression, env *object.Environment) object.Object {
+	left := e.Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+	if node.Operator == "&&" && !isTruthy(left) {
+		return falseObj
+	}
+	if node.Operator == "||" && isTruthy(left) {
+		return trueObj
+	}
+	right := e.Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+	return nativeBoolToBooleanObject(isTruthy(right))
+}
+
+// evalIdentifier evaluates to the value bound to the identifier in the environment, or to the builtin function with the
+// same name if the identifier isn't bound.
+func (e *Evaluator) evalIdentifier(node ast.Identifier, env *object.Environment) object.Object {
+	if val, ok := env.Get(node.Value); ok {
+		return val
+	}
+	if builtin, ok := e.builtins[node.Value]; ok {
+		return builtin
+	}
+	return newError("identifier not found: %s", node.Value)
+}
+
+// evalExpressions evaluates the given expressions in order. If any of them evaluate to an error, then evaluation stops
+// and only the error is returned.
+func (e *Evaluator) evalExpressions(exprs []ast.Expression, env *object.Environment) []object.Object {
+	results := make([]object.Object, len(exprs))
+	for i, expr := range exprs {
+		result := e.Eval(expr, env)
+		if isError(result) {
+			return []object.Object{result}
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// applyFunction calls the given function with the given arguments. The body of the function is evaluated in a new
+// environment which binds the parameters of the function to the arguments and is enclosed by the environment that the
+// function was defined in, so that functions close over the bindings which were visible where they were defined.
+// Calling a value which isn't a function or builtin evaluates to an error which includes the type of the value.
+func (e *Evaluator) applyFunction(obj object.Object, args []object.Object) object.Object {
+	if builtin, ok := obj.(*object.Builtin); ok {
+		return builtin.Fn(args...)
+	}
+	function, ok := obj.(*object.Function)
+	if !ok {
+		return newError("not a function: %s", obj.Type())
+	}
+	if len(args) != len(function.Parameters) {
+		return newError("wrong number of arguments: want %d, got %d", len(function.Parameters), len(args))
+	}
+	env := object.NewEnclosedEnvironment(function.Env)
+	for i, param := range function.Parameters {
+		env.Set(param.Value, args[i])
+	}
+	result := e.Eval(function.Body, env)
+	switch result := result.(type) {
+	case *object.ReturnValue:
+		return result.Value
+	case *object.Break, *object.Continue:
+		return unmatchedLoopControlError(result)
+	}
+	return result
+}
+
+func (e *Evaluator) evalPrefixExpression(operator string, right object.Object) object.Object {
+	switch operator {
+	case "!":
+		return evalBangOperatorExpression(right)
+	case "-":
+		return e.evalMinusPrefixOperatorExpression(right)
+	default:
+		return newError("unknown operator: %s%s", operator, right.Type())
+	}
+}
+
+// evalBangOperatorExpression evaluates !right to true if right is falsy and false otherwise.
+func evalBangOperatorExpression(right object.Object) object.Object {
+	return nativeBoolToBooleanObject(!isTruthy(right))
+}
+
+// evalMinusPrefixOperatorExpression evaluates -right, which is only supported for integers. Negative integer literals
+// like -5 are parsed as the - operator applied to a positive literal, so they're evaluated here too.
+func (e *Evaluator) evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	if e.bigIntegers && isInteger(right) {
+		return newInteger(new(big.Int).Neg(toBigInt(right)))
+	}
+	integer, ok := right.(*object.Integer)
+	if !ok {
+		return newError("unknown operator: -%s", right.Type())
+	}
+	return &object.Integer{Value: -integer.Value}
+}
+
+func (e *Evaluator) evalInfixExpression(operator string, left, right object.Object) object.Object {
+	switch {
+	case e.bigIntegers && isInteger(left) && isInteger(right):
+		return evalBigIntegerInfixExpression(operator, left, right)
+	case operator == "==":
+		// values of different types are never equal, so they can be compared without it being an error
+		return nativeBoolToBooleanObject(object.Equals(left, right))
+	case operator == "!=":
+		return nativeBoolToBooleanObject(!object.Equals(left, right))
+	case left.Type() == object.IntegerObj && right.Type() == object.IntegerObj:
+		return evalIntegerInfixExpression(operator, left.(*object.Integer), right.(*object.Integer))
+	case left.Type() == object.StringObj && right.Type() == object.StringObj:
+		return evalStringInfixExpression(operator, left.(*object.String), right.(*object.String))
+	case left.Type() != right.Type():
+		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalIntegerInfixExpression(operator string, left, right *object.Integer) object.Object {
+	switch operator {
+	case "+":
+		return &object.Integer{Value: left.Value + right.Value}
+	case "-":
+		return &object.Integer{Value: left.Value - right.Value}
+	case "*":
+		return &object.Integer{Value: left.Value * right.Value}
+	case "/":
+		if right.Value == 0 {
+			return newError("division by zero")
+		}
+		return &object.Integer{Value: left.Value / right.Value}
+	case "%":
+		if right.Value == 0 {
+			return newError("division by zero")
+		}
+		return &object.Integer{Value: left.Value % right.Value}
+	case "<":
+		return nativeBoolToBooleanObject(left.Value < right.Value)
+	case ">":
+		return nativeBoolToBooleanObject(left.Value > right.Value)
+	case "<=":
+		return nativeBoolToBooleanObject(left.Value <= right.Value)
+	case ">=":
+		return nativeBoolToBooleanObject(left.Value >= right.Value)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalBigIntegerInfixExpression evaluates left <operator> right where both operands are integers or big integers, by
+// promoting them both to big integers. The results of arithmetic are converted back to integers if they fit in one.
+func evalBigIntegerInfixExpression(operator string, left, right object.Object) object.Object {
+	x, y := toBigInt(left), toBigInt(right)
+	switch operator {
+	case "+":
+		return newInteger(new(big.Int).Add(x, y))
+	case "-":
+		return newInteger(new(big.Int).Sub(x, y))
+	case "*":
+		return newInteger(new(big.Int).Mul(x, y))
+	case "/":
+		if y.Sign() == 0 {
+			return newError("division by zero")
+		}
+		return newInteger(new(big.Int).Quo(x, y))
+	case "%":
+		if y.Sign() == 0 {
+			return newError("division by zero")
+		}
+		return newInteger(new(big.Int).Rem(x, y))
+	case "<":
+		return nativeBoolToBooleanObject(x.Cmp(y) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(x.Cmp(y) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(x.Cmp(y) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(x.Cmp(y) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(x.Cmp(y) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(x.Cmp(y) != 0)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// isInteger returns whether the given value is an integer or a big integer.
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.IntegerObj || obj.Type() == object.BigIntegerObj
+}
+
+// toBigInt returns the value of an integer or big integer as a [big.Int]. The value of a big integer is returned
+// directly, so it mustn't be modified.
+func toBigInt(obj object.Object) *big.Int {
+	if integer, ok := obj.(*object.Integer); ok {
+		return big.NewInt(integer.Value)
+	}
+	return obj.(*object.BigInteger).Value
+}
+
+// newInteger returns an integer with the given value if it fits in one, and a big integer otherwise.
+func newInteger(value *big.Int) object.Object {
+	if value.IsInt64() {
+		return &object.Integer{Value: value.Int64()}
+	}
+	return &object.BigInteger{Value: value}
+}
+
+// evalStringInfixExpression evaluates left <operator> right where both operands are strings. Strings are ordered
+// lexicographically by their bytes.
+func evalStringInfixExpression(operator string, left, right *object.String) object.Object {
+	switch operator {
+	case "+":
+		return &object.String{Value: left.Value + right.Value}
+	case "<":
+		return nativeBoolToBooleanObject(left.Value < right.Value)
+	case ">":
+		return nativeBoolToBooleanObject(left.Value > right.Value)
+	case "<=":
+		return nativeBoolToBooleanObject(left.Value <= right.Value)
+	case ">=":
+		return nativeBoolToBooleanObject(left.Value >= right.Value)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalBooleanInfixExpression evaluates left <operator> right where both operands are booleans. Booleans can be
+// compared by identity since there's only one true and one false value.
+func evalIndexExpression(left, index object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		return evalArrayIndexExpression(left, index)
+	case *object.Hash:
+		return evalHashIndexExpression(left, index)
+	case *object.String:
+		return evalStringIndexExpression(left, index)
+	default:
+		return newError("index operator not supported: %s", left.Type())
+	}
+}
+
+// evalStringIndexExpression evaluates str[index] to a string containing the character at the index, so that indexing
+// never splits a multibyte character. This is consistent with len counting the characters in a string. Like arrays,
+// indexing outside the bounds of the string evaluates to null.
+func evalStringIndexExpression(str *object.String, index object.Object) object.Object {
+	integer, ok := index.(*object.Integer)
+	if !ok {
+		return newError("string index must be %s, got %s", object.IntegerObj, index.Type())
+	}
+	runes := []rune(str.Value)
+	if integer.Value < 0 || integer.Value >= int64(len(runes)) {
+		return nullObj
+	}
+	return &object.String{Value: string(runes[integer.Value])}
+}
+
+// evalArrayIndexExpression evaluates array[index]. Indexing outside the bounds of the array, including with a negative
+// index, evaluates to null rather than an error so that programs can check whether an element exists without having to
+// know the length of the array.
+func evalArrayIndexExpression(array *object.Array, index object.Object) object.Object {
+	integer, ok := index.(*object.Integer)
+	if !ok {
+		return newError("array index must be %s, got %s", object.IntegerObj, index.Type())
+	}
+	if integer.Value < 0 || integer.Value >= int64(len(array.Elements)) {
+		return nullObj
+	}
+	return array.Elements[integer.Value]
+}
+
+// evalHashIndexExpression evaluates hash[index]. Indexing with a key which isn't in the hash evaluates to null.
+func evalHashIndexExpression(hash *object.Hash, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+	pair, ok := hash.Pairs[key.HashKey()]
+	if !ok {
+		return nullObj
+	}
+	return pair.Value
+}
+
+// evalHashLiteral evaluates the pairs of the hash literal in order. If a key is repeated, then the last value for the
+// key is used.
+func (e *Evaluator) evalHashLiteral(node ast.HashLiteral, env *object.Environment) object.Object {
+	pairs := make(map[object.HashKey]object.HashPair, len(node.Pairs))
+	for _, pair := range node.Pairs {
+		key := e.Eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+		value := e.Eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+		pairs[hashKey.HashKey()] = object.HashPair{Key: hashKey, Value: value}
+	}
+	return &object.Hash{Pairs: pairs}
+}
+
+// evalIfExpression evaluates the consequence of the if expression if its condition is truthy. Otherwise, the
+// alternative is evaluated if there is one. If neither branch is evaluated, then the expression evaluates to null.
+func (e *Evaluator) evalIfExpression(node ast.IfExpression, env *object.Environment) object.Object {
+	condition := e.Eval(node.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+	switch {
+	case isTruthy(condition):
+		return e.Eval(node.Consequence, env)
+	case node.Alternative != nil:
+		return e.Eval(node.Alternative, env)
+	default:
+		return nullObj
+	}
+}
+
+// isTruthy returns whether the given value is considered true when used as the condition of an if expression or while
+// loop, or as the operand of !, &&, or ||. false and null are falsy and every other value is truthy, including 0, "",
+// and empty arrays and hashes. The types of the values are checked rather than comparing them to the true, false, and
+// null singletons so that values which weren't created by the evaluator are treated in the same way.
+func isTruthy(obj object.Object) bool {
+	switch obj := obj.(type) {
+	case *object.Boolean:
+		return obj.Value
+	case *object.Null:
+		return false
+	default:
+		return true
+	}
+}
+
+func nativeBoolToBooleanObject(value bool) *object.Boolean {
+	if value {
+		return trueObj
+	}
+	return falseObj
+}
+
+func newError(format string, a ...any) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...)}
+}
+
+// unmatchedLoopControlError returns the error for an [object.Break] or [object.Continue] which wasn't stopped by a loop,
+// which happens if the break or continue statement isn't inside a loop, or isn't inside a loop with its label. Loops
+// outside of a function body can't be broken out of or continued from inside it.
+func unmatchedLoopControlError(obj object.Object) *object.Error {
+	var keyword, label string
+	switch obj := obj.(type) {
+	case *object.Break:
+		keyword, label = "break", obj.Label
+	case *object.Continue:
+		keyword, label = "continue", obj.Label
+	}
+	if label == "" {
+		return newError("%s outside of a loop", keyword)
+	}
+	return newError("%s to unknown label: %s", keyword, label)
+}
+
+func isError(obj object.Object) bool {
+	return obj.Type() == object.ErrorObj
+}
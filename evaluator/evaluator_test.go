@@ -0,0 +1,829 @@
+package evaluator_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/evaluator"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
+)
+
+func TestEval(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want object.Object
+	}{
+		{name: "Integer", src: "5", want: &object.Integer{Value: 5}},
+		{name: "LastStatement", src: "5; 10", want: &object.Integer{Value: 10}},
+		{name: "EmptyProgram", src: "", want: &object.Null{}},
+		{name: "Negation", src: "-5", want: &object.Integer{Value: -5}},
+		{name: "DoubleNegation", src: "--5", want: &object.Integer{Value: 5}},
+		{name: "NegatedGroup", src: "-(-10)", want: &object.Integer{Value: 10}},
+		{name: "NegatedExpression", src: "-(2 * 3)", want: &object.Integer{Value: -6}},
+		{name: "NegatedIdentifier", src: "let x = 5; -x", want: &object.Integer{Value: -5}},
+		{name: "MinInt64", src: "-9223372036854775807 - 1", want: &object.Integer{Value: -9223372036854775808}},
+		{name: "Addition", src: "5 + 5 + 5 + 5 - 10", want: &object.Integer{Value: 10}},
+		{name: "Multiplication", src: "2 * 2 * 2 * 2 * 2", want: &object.Integer{Value: 32}},
+		{name: "Precedence", src: "5 + 5 * 2", want: &object.Integer{Value: 15}},
+		{name: "NegativeOperand", src: "-50 + 100 + -50", want: &object.Integer{Value: 0}},
+		{name: "Division", src: "50 / 2 * 2 + 10", want: &object.Integer{Value: 60}},
+		{name: "TruncatedDivision", src: "7 / 2", want: &object.Integer{Value: 3}},
+		{name: "Modulo", src: "17 % 5", want: &object.Integer{Value: 2}},
+		{name: "Grouping", src: "(5 + 10 * 2 + 15 / 3) * 2 + -10", want: &object.Integer{Value: 50}},
+		{name: "True", src: "true", want: &object.Boolean{Value: true}},
+		{name: "False", src: "false", want: &object.Boolean{Value: false}},
+		{name: "LessThan", src: "1 < 2", want: &object.Boolean{Value: true}},
+		{name: "NotLessThan", src: "2 < 1", want: &object.Boolean{Value: false}},
+		{name: "GreaterThan", src: "2 > 1", want: &object.Boolean{Value: true}},
+		{name: "NotGreaterThan", src: "1 > 1", want: &object.Boolean{Value: false}},
+		{name: "LessThanOrEqualWhenEqual", src: "5 <= 5", want: &object.Boolean{Value: true}},
+		{name: "LessThanOrEqualWhenLess", src: "4 <= 5", want: &object.Boolean{Value: true}},
+		{name: "NotLessThanOrEqual", src: "6 <= 5", want: &object.Boolean{Value: false}},
+		{name: "GreaterThanOrEqualWhenEqual", src: "7 >= 7", want: &object.Boolean{Value: true}},
+		{name: "GreaterThanOrEqualWhenGreater", src: "8 >= 7", want: &object.Boolean{Value: true}},
+		{name: "NotGreaterThanOrEqual", src: "6 >= 7", want: &object.Boolean{Value: false}},
+		{name: "AndTrue", src: "true && true", want: &object.Boolean{Value: true}},
+		{name: "AndFalse", src: "true && false", want: &object.Boolean{Value: false}},
+		{name: "OrTrue", src: "false || true", want: &object.Boolean{Value: true}},
+		{name: "OrFalse", src: "false || false", want: &object.Boolean{Value: false}},
+		{name: "AndTruthyOperands", src: `1 && "a"`, want: &object.Boolean{Value: true}},
+		{name: "AndNullOperand", src: "let f = fn() {}; 1 && f()", want: &object.Boolean{Value: false}},
+		{name: "OrTruthyOperand", src: "false || 0", want: &object.Boolean{Value: true}},
+		{name: "AndShortCircuits", src: "false && undefined", want: &object.Boolean{Value: false}},
+		{name: "AndShortCircuitsCall", src: "false && f()", want: &object.Boolean{Value: false}},
+		{name: "OrShortCircuits", src: "true || undefined", want: &object.Boolean{Value: true}},
+		{name: "OrShortCircuitsCall", src: "true || g()", want: &object.Boolean{Value: true}},
+		{
+			name: "AndEvaluatesRightOperand",
+			src:  "true && undefined",
+			want: &object.Error{Message: "identifier not found: undefined"},
+		},
+		{
+			name: "OrEvaluatesRightOperand",
+			src:  "false || undefined",
+			want: &object.Error{Message: "identifier not found: undefined"},
+		},
+		{name: "ErrorInLogicalLeftOperand", src: "-true || true", want: &object.Error{Message: "unknown operator: -BOOLEAN"}},
+		{name: "LogicalPrecedence", src: "false && false || true", want: &object.Boolean{Value: true}},
+		{
+			name: "LessThanOrEqualTypeMismatch",
+			src:  "5 <= true",
+			want: &object.Error{Message: "type mismatch: INTEGER <= BOOLEAN"},
+		},
+		{
+			name: "GreaterThanOrEqualOnBooleans",
+			src:  "true >= false",
+			want: &object.Error{Message: "unknown operator: BOOLEAN >= BOOLEAN"},
+		},
+		{name: "IntegersEqual", src: "1 == 1", want: &object.Boolean{Value: true}},
+		{name: "IntegersNotEqual", src: "1 != 2", want: &object.Boolean{Value: true}},
+		{name: "BooleansEqual", src: "false == false", want: &object.Boolean{Value: true}},
+		{name: "BooleansNotEqual", src: "true != false", want: &object.Boolean{Value: true}},
+		{name: "ComparisonResultsEqual", src: "(1 < 2) == true", want: &object.Boolean{Value: true}},
+		{name: "ComparisonResultsNotEqual", src: "(1 > 2) == true", want: &object.Boolean{Value: false}},
+		{name: "BangTrue", src: "!true", want: &object.Boolean{Value: false}},
+		{name: "BangFalse", src: "!false", want: &object.Boolean{Value: true}},
+		{name: "BangInteger", src: "!5", want: &object.Boolean{Value: false}},
+		{name: "DoubleBangTrue", src: "!!true", want: &object.Boolean{Value: true}},
+		{name: "DoubleBangInteger", src: "!!5", want: &object.Boolean{Value: true}},
+		{name: "Let", src: "let a = 5; a", want: &object.Integer{Value: 5}},
+		{name: "LetExpression", src: "let a = 5 * 5; a", want: &object.Integer{Value: 25}},
+		{name: "LetIdentifier", src: "let a = 5; let b = a; b", want: &object.Integer{Value: 5}},
+		{name: "LetMultiple", src: "let a = 5; let b = a; let c = a + b + 5; c", want: &object.Integer{Value: 15}},
+		{name: "LetRebinding", src: "let x = 5; let x = x + 1; x", want: &object.Integer{Value: 6}},
+		{name: "LetStatementValue", src: "let x = 5", want: &object.Null{}},
+		{
+			name: "UndefinedIdentifier",
+			src:  "foobar",
+			want: &object.Error{Message: "identifier not found: foobar"},
+		},
+		{
+			name: "LetUndefinedIdentifier",
+			src:  "let x = y",
+			want: &object.Error{Message: "identifier not found: y"},
+		},
+		{name: "Call", src: "let identity = fn(x) { x; }; identity(5)", want: &object.Integer{Value: 5}},
+		{name: "CallFunctionLiteral", src: "fn(x) { x * 2 }(5)", want: &object.Integer{Value: 10}},
+		{name: "CallNoArguments", src: "let five = fn() { 5 }; five()", want: &object.Integer{Value: 5}},
+		{name: "CallEmptyBody", src: "fn() {}()", want: &object.Null{}},
+		{
+			name: "CallMultipleArguments",
+			src:  "let add = fn(x, y) { x + y; }; add(5 + 5, add(5, 5))",
+			want: &object.Integer{Value: 20},
+		},
+		{
+			name: "Closure",
+			src: `let newAdder = fn(x) { fn(y) { x + y } };
+let addTwo = newAdder(2);
+addTwo(3)`,
+			want: &object.Integer{Value: 5},
+		},
+		{
+			name: "ClosureCapturesDefiningEnvironment",
+			src: `let x = 10;
+let getX = fn() { x };
+let callWithX = fn(x, f) { f() };
+callWithX(20, getX)`,
+			want: &object.Integer{Value: 10},
+		},
+		{
+			name: "ParameterShadowsOuterBinding",
+			src:  "let x = 1; let f = fn(x) { let y = x; y }; f(2); x",
+			want: &object.Integer{Value: 1},
+		},
+		{name: "CallNonFunction", src: "5()", want: &object.Error{Message: "not a function: INTEGER"}},
+		{name: "CallString", src: `"x"(1)`, want: &object.Error{Message: "not a function: STRING"}},
+		{name: "CallNull", src: "fn() {}()()", want: &object.Error{Message: "not a function: NULL"}},
+		{name: "CallArray", src: "[1](0)", want: &object.Error{Message: "not a function: ARRAY"}},
+		{
+			name: "CallResultOfCall",
+			src:  "let f = fn() { 1 }; f()()",
+			want: &object.Error{Message: "not a function: INTEGER"},
+		},
+		{
+			name: "CallWithTooFewArguments",
+			src:  "fn(x, y) { x }(1)",
+			want: &object.Error{Message: "wrong number of arguments: want 2, got 1"},
+		},
+		{
+			name: "CallWithErrorArgument",
+			src:  "fn(x) { x }(y)",
+			want: &object.Error{Message: "identifier not found: y"},
+		},
+		{name: "IfTrue", src: "if (true) { 10 }", want: &object.Integer{Value: 10}},
+		{name: "IfFalse", src: "if (false) { 10 }", want: &object.Null{}},
+		{name: "IfTruthy", src: "if (1) { 10 }", want: &object.Integer{Value: 10}},
+		{name: "IfCondition", src: "if (1 < 2) { 10 }", want: &object.Integer{Value: 10}},
+		{name: "IfElseTrue", src: "if (1 < 2) { 10 } else { 20 }", want: &object.Integer{Value: 10}},
+		{name: "IfElseFalse", src: "if (1 > 2) { 10 } else { 20 }", want: &object.Integer{Value: 20}},
+		{name: "IfEmptyBlock", src: "if (true) {}", want: &object.Null{}},
+		{name: "Return", src: "return 10;", want: &object.Integer{Value: 10}},
+		{name: "ReturnWithoutValue", src: "return; 9", want: &object.Null{}},
+		{name: "ReturnStopsProgram", src: "return 10; 9;", want: &object.Integer{Value: 10}},
+		{name: "ReturnExpression", src: "return 2 * 5; 9;", want: &object.Integer{Value: 10}},
+		{name: "ReturnAfterStatement", src: "9; return 2 * 5; 9;", want: &object.Integer{Value: 10}},
+		{
+			name: "ReturnInNestedBlock",
+			src: `if (10 > 1) {
+  if (10 > 1) {
+    return 10;
+  }
+  return 1;
+}`,
+			want: &object.Integer{Value: 10},
+		},
+		{
+			name: "ReturnFromFunction",
+			src:  "let f = fn(x) { return x; x + 10; }; f(10)",
+			want: &object.Integer{Value: 10},
+		},
+		{
+			name: "ReturnFromNestedIfInFunction",
+			src: `let f = fn(x) {
+  if (x > 1) {
+    if (true) {
+      return x;
+    }
+    return 0;
+  }
+  return -1;
+};
+f(5)`,
+			want: &object.Integer{Value: 5},
+		},
+		{
+			name: "StatementsAfterReturnNotEvaluated",
+			src: `let f = fn() {
+  return 1;
+  undefined
+};
+f()`,
+			want: &object.Integer{Value: 1},
+		},
+		{
+			name: "ReturnOnlyExitsInnermostFunction",
+			src:  "let inner = fn() { return 1; 2 }; let outer = fn() { inner() + 10 }; outer()",
+			want: &object.Integer{Value: 11},
+		},
+		{name: "DivisionByZero", src: "5 / 0", want: &object.Error{Message: "division by zero"}},
+		{name: "ModuloByZero", src: "5 % 0", want: &object.Error{Message: "division by zero"}},
+		{name: "TypeMismatch", src: "5 + true", want: &object.Error{Message: "type mismatch: INTEGER + BOOLEAN"}},
+		{
+			name: "TypeMismatchStopsProgram",
+			src:  "5 + true; 5",
+			want: &object.Error{Message: "type mismatch: INTEGER + BOOLEAN"},
+		},
+		{name: "NegatedBoolean", src: "-true", want: &object.Error{Message: "unknown operator: -BOOLEAN"}},
+		{name: "NegatedNull", src: "-fn() {}()", want: &object.Error{Message: "unknown operator: -NULL"}},
+		{name: "NegatedArray", src: "-[1]", want: &object.Error{Message: "unknown operator: -ARRAY"}},
+		{
+			name: "UnknownBooleanOperator",
+			src:  "true + false",
+			want: &object.Error{Message: "unknown operator: BOOLEAN + BOOLEAN"},
+		},
+		{
+			name: "UnknownBooleanOperatorStopsProgram",
+			src:  "5; true + false; 5",
+			want: &object.Error{Message: "unknown operator: BOOLEAN + BOOLEAN"},
+		},
+		{
+			name: "ErrorInPrefixOperand",
+			src:  "!(-true)",
+			want: &object.Error{Message: "unknown operator: -BOOLEAN"},
+		},
+		{
+			name: "ErrorInLeftInfixOperand",
+			src:  "(true + false) + 5",
+			want: &object.Error{Message: "unknown operator: BOOLEAN + BOOLEAN"},
+		},
+		{
+			name: "ErrorInRightInfixOperand",
+			src:  "5 + (1 + true)",
+			want: &object.Error{Message: "type mismatch: INTEGER + BOOLEAN"},
+		},
+		{
+			name: "ErrorInBlock",
+			src:  "if (10 > 1) { true + false; 5 }",
+			want: &object.Error{Message: "unknown operator: BOOLEAN + BOOLEAN"},
+		},
+		{
+			name: "ErrorInNestedBlock",
+			src: `if (10 > 1) {
+  if (10 > 1) {
+    return true + false;
+  }
+  return 1;
+}`,
+			want: &object.Error{Message: "unknown operator: BOOLEAN + BOOLEAN"},
+		},
+		{
+			name: "ErrorInCondition",
+			src:  "if (x) { 1 } else { 2 }",
+			want: &object.Error{Message: "identifier not found: x"},
+		},
+		{
+			name: "ErrorInFunction",
+			src:  "let f = fn() { -true; 5 }; f(); 10",
+			want: &object.Error{Message: "unknown operator: -BOOLEAN"},
+		},
+		{
+			name: "ErrorInFunctionExpression",
+			src:  "y()",
+			want: &object.Error{Message: "identifier not found: y"},
+		},
+		{name: "String", src: `"Hello World!"`, want: &object.String{Value: "Hello World!"}},
+		{name: "StringConcatenation", src: `"foo" + "bar"`, want: &object.String{Value: "foobar"}},
+		{
+			name: "StringConcatenationMultiple",
+			src:  `let greet = fn(name) { "Hello, " + name + "!" }; greet("Monkey")`,
+			want: &object.String{Value: "Hello, Monkey!"},
+		},
+		{name: "StringPlusInteger", src: `"foo" + 5`, want: &object.Error{Message: "type mismatch: STRING + INTEGER"}},
+		{name: "IntegerPlusString", src: `5 + "foo"`, want: &object.Error{Message: "type mismatch: INTEGER + STRING"}},
+		{name: "StringLessThan", src: `"a" < "b"`, want: &object.Boolean{Value: true}},
+		{name: "StringNotLessThan", src: `"b" < "a"`, want: &object.Boolean{Value: false}},
+		{name: "StringGreaterThan", src: `"b" > "a"`, want: &object.Boolean{Value: true}},
+		{name: "StringPrefixLessThan", src: `"ab" < "abc"`, want: &object.Boolean{Value: true}},
+		{name: "StringUppercaseLessThanLowercase", src: `"Z" < "a"`, want: &object.Boolean{Value: true}},
+		{name: "StringLessThanOrEqual", src: `"a" <= "a"`, want: &object.Boolean{Value: true}},
+		{name: "StringGreaterThanOrEqual", src: `"a" >= "b"`, want: &object.Boolean{Value: false}},
+		{name: "StringsEqual", src: `"x" == "x"`, want: &object.Boolean{Value: true}},
+		{name: "StringsNotEqual", src: `"x" != "y"`, want: &object.Boolean{Value: true}},
+		{name: "StringEqualsInteger", src: `"x" == 5`, want: &object.Boolean{Value: false}},
+		{name: "StringNotEqualsInteger", src: `"5" != 5`, want: &object.Boolean{Value: true}},
+		{name: "IntegerEqualsBoolean", src: "1 == true", want: &object.Boolean{Value: false}},
+		{
+			name: "StringLessThanInteger",
+			src:  `"a" < 5`,
+			want: &object.Error{Message: "type mismatch: STRING < INTEGER"},
+		},
+		{
+			name: "StringUnknownOperator",
+			src:  `"foo" - "bar"`,
+			want: &object.Error{Message: "unknown operator: STRING - STRING"},
+		},
+		{name: "NegatedString", src: `-"foo"`, want: &object.Error{Message: "unknown operator: -STRING"}},
+		{
+			name: "Array",
+			src:  "[1, 2 * 2, 3 + 3]",
+			want: &object.Array{
+				Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 6},
+				},
+			},
+		},
+		{name: "EmptyArray", src: "[]", want: &object.Array{Elements: []object.Object{}}},
+		{name: "ErrorInArrayElement", src: "[1, x]", want: &object.Error{Message: "identifier not found: x"}},
+		{name: "IndexFirst", src: "[1, 2, 3][0]", want: &object.Integer{Value: 1}},
+		{name: "IndexMiddle", src: "[1, 2, 3][1]", want: &object.Integer{Value: 2}},
+		{name: "IndexLast", src: "[1, 2, 3][2]", want: &object.Integer{Value: 3}},
+		{name: "IndexExpression", src: "let i = 0; [1][i + 0]", want: &object.Integer{Value: 1}},
+		{name: "IndexIdentifier", src: "let myArray = [1, 2, 3]; myArray[2];", want: &object.Integer{Value: 3}},
+		{
+			name: "IndexSum",
+			src:  "let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];",
+			want: &object.Integer{Value: 6},
+		},
+		{name: "IndexNested", src: "[[1, 2], [3, 4]][1][0]", want: &object.Integer{Value: 3}},
+		{name: "IndexOutOfRange", src: "[1, 2, 3][3]", want: &object.Null{}},
+		{name: "IndexNegative", src: "[1, 2, 3][-1]", want: &object.Null{}},
+		{name: "ArraysEqual", src: `[1, "a", [true]] == [1, "a", [true]]`, want: &object.Boolean{Value: true}},
+		{name: "ArraysNotEqual", src: "[1, 2] != [1, 3]", want: &object.Boolean{Value: true}},
+		{name: "ArraysOfDifferentLengthsEqual", src: "[1] == [1, 1]", want: &object.Boolean{Value: false}},
+		{name: "ArrayEqualToItself", src: "let a = [1]; a == a", want: &object.Boolean{Value: true}},
+		{
+			name: "HashesWithDifferentInsertionOrderEqual",
+			src:  `{"a": 1, "b": [2]} == {"b": [2], "a": 1}`,
+			want: &object.Boolean{Value: true},
+		},
+		{name: "HashesNotEqual", src: `{"a": 1} != {"a": 2}`, want: &object.Boolean{Value: true}},
+		{name: "NestedStructuresEqual", src: `[{"a": [{}]}] == [{"a": [{}]}]`, want: &object.Boolean{Value: true}},
+		{
+			name: "ArraysUnknownOperator",
+			src:  "[1] < [2]",
+			want: &object.Error{Message: "unknown operator: ARRAY < ARRAY"},
+		},
+		{name: "ArrayEqualsHash", src: "[] == {}", want: &object.Boolean{Value: false}},
+		{name: "NullEqualsNull", src: "fn() {}() == fn() {}()", want: &object.Boolean{Value: true}},
+		{name: "NullNotEqualsNull", src: "fn() {}() != fn() {}()", want: &object.Boolean{Value: false}},
+		{name: "FunctionEqualsItself", src: "let f = fn() {}; f == f", want: &object.Boolean{Value: true}},
+		{
+			name: "FunctionEqualsOtherFunction",
+			src:  "let f = fn() {}; let g = fn() {}; f == g",
+			want: &object.Boolean{Value: false},
+		},
+		{name: "FunctionNotEqualsItself", src: "let f = fn() {}; f != f", want: &object.Boolean{Value: false}},
+		{
+			name: "FunctionsUnknownOperator",
+			src:  "let f = fn() {}; f < f",
+			want: &object.Error{Message: "unknown operator: FUNCTION < FUNCTION"},
+		},
+		{name: "StringIndexFirst", src: `"hello"[0]`, want: &object.String{Value: "h"}},
+		{name: "StringIndexLast", src: `let s = "hello"; s[len(s) - 1]`, want: &object.String{Value: "o"}},
+		{name: "StringIndexOutOfRange", src: `"hello"[5]`, want: &object.Null{}},
+		{name: "StringIndexNegative", src: `"hello"[-1]`, want: &object.Null{}},
+		{name: "StringIndexEmpty", src: `""[0]`, want: &object.Null{}},
+		{name: "StringIndexMultibyteCharacter", src: `"héllo"[1]`, want: &object.String{Value: "é"}},
+		{name: "StringIndexAfterMultibyteCharacter", src: `"héllo"[2]`, want: &object.String{Value: "l"}},
+		{name: "StringIndexLastMultibyteCharacter", src: `let s = "日本"; s[len(s) - 1]`, want: &object.String{Value: "本"}},
+		{name: "StringIndexOutOfRangeOfCharacters", src: `"日本"[2]`, want: &object.Null{}},
+		{
+			name: "StringIndexNotInteger",
+			src:  `"hello"["h"]`,
+			want: &object.Error{Message: "string index must be INTEGER, got STRING"},
+		},
+		{name: "IndexEmpty", src: "[][0]", want: &object.Null{}},
+		{
+			name: "IndexNonInteger",
+			src:  `[1, 2, 3]["0"]`,
+			want: &object.Error{Message: "array index must be INTEGER, got STRING"},
+		},
+		{name: "IndexNonArray", src: "5[0]", want: &object.Error{Message: "index operator not supported: INTEGER"}},
+		{name: "ErrorInIndex", src: "[1][x]", want: &object.Error{Message: "identifier not found: x"}},
+		{
+			name: "Hash",
+			src: `let two = "two";
+{
+  "one": 10 - 9,
+  two: 1 + 1,
+  "thr" + "ee": 6 / 2,
+  4: 4,
+  true: 5,
+  false: 6
+}`,
+			want: &object.Hash{
+				Pairs: map[object.HashKey]object.HashPair{
+					(&object.String{Value: "one"}).HashKey(): {
+						Key:   &object.String{Value: "one"},
+						Value: &object.Integer{Value: 1},
+					},
+					(&object.String{Value: "two"}).HashKey(): {
+						Key:   &object.String{Value: "two"},
+						Value: &object.Integer{Value: 2},
+					},
+					(&object.String{Value: "three"}).HashKey(): {
+						Key:   &object.String{Value: "three"},
+						Value: &object.Integer{Value: 3},
+					},
+					(&object.Integer{Value: 4}).HashKey(): {
+						Key:   &object.Integer{Value: 4},
+						Value: &object.Integer{Value: 4},
+					},
+					(&object.Boolean{Value: true}).HashKey(): {
+						Key:   &object.Boolean{Value: true},
+						Value: &object.Integer{Value: 5},
+					},
+					(&object.Boolean{Value: false}).HashKey(): {
+						Key:   &object.Boolean{Value: false},
+						Value: &object.Integer{Value: 6},
+					},
+				},
+			},
+		},
+		{name: "EmptyHash", src: "{}", want: &object.Hash{Pairs: map[object.HashKey]object.HashPair{}}},
+		{
+			name: "HashRepeatedKey",
+			src:  `{"a": 1, "a": 2}`,
+			want: &object.Hash{
+				Pairs: map[object.HashKey]object.HashPair{
+					(&object.String{Value: "a"}).HashKey(): {
+						Key:   &object.String{Value: "a"},
+						Value: &object.Integer{Value: 2},
+					},
+				},
+			},
+		},
+		{name: "HashIndexString", src: `{"foo": 5}["foo"]`, want: &object.Integer{Value: 5}},
+		{name: "HashIndexIdentifier", src: `let key = "foo"; {"foo": 5}[key]`, want: &object.Integer{Value: 5}},
+		{name: "HashIndexInteger", src: "{5: 5}[5]", want: &object.Integer{Value: 5}},
+		{name: "HashIndexTrue", src: "{true: 5}[true]", want: &object.Integer{Value: 5}},
+		{name: "HashIndexFalse", src: "{false: 5}[false]", want: &object.Integer{Value: 5}},
+		{name: "HashIndexMissingKey", src: `{"foo": 5}["bar"]`, want: &object.Null{}},
+		{name: "HashIndexEmpty", src: `{}["foo"]`, want: &object.Null{}},
+		{name: "HashKeysOfDifferentTypes", src: `{1: "int", true: "bool"}[true]`, want: &object.String{Value: "bool"}},
+		{
+			name: "HashUnhashableKey",
+			src:  `{fn(x) { x }: "fn"}`,
+			want: &object.Error{Message: "unusable as hash key: FUNCTION"},
+		},
+		{
+			name: "HashIndexUnhashableKey",
+			src:  `{"name": "Monkey"}[[1]]`,
+			want: &object.Error{Message: "unusable as hash key: ARRAY"},
+		},
+		{name: "ErrorInHashValue", src: `{"a": x}`, want: &object.Error{Message: "identifier not found: x"}},
+		{name: "Assign", src: "let x = 1; x = x + 1; x", want: &object.Integer{Value: 2}},
+		{
+			name: "AssignInFunctionUpdatesOuterBinding",
+			src:  "let x = 1; let f = fn() { x = 5; }; f(); x",
+			want: &object.Integer{Value: 5},
+		},
+		{name: "AssignUnboundIdentifier", src: "x = 1", want: &object.Error{Message: "identifier not found: x"}},
+		{name: "ErrorInAssignValue", src: "let x = 1; x = y", want: &object.Error{Message: "identifier not found: y"}},
+		{
+			name: "WhileCountingLoop",
+			src:  "let i = 0; let sum = 0; while (i < 5) { sum = sum + i; i = i + 1; } sum",
+			want: &object.Integer{Value: 10},
+		},
+		{name: "WhileEvaluatesToNull", src: "let i = 0; while (i < 3) { i = i + 1; i }", want: &object.Null{}},
+		{name: "WhileFalseCondition", src: "while (false) { x }", want: &object.Null{}},
+		{
+			name: "ReturnFromWhileInFunction",
+			src:  "let f = fn() { let i = 0; while (true) { if (i == 3) { return i; } i = i + 1; } }; f()",
+			want: &object.Integer{Value: 3},
+		},
+		{
+			name: "ErrorInWhileBody",
+			src:  "let i = 0; while (i < 3) { i = i + 1; if (i == 2) { i + true; } } i",
+			want: &object.Error{Message: "type mismatch: INTEGER + BOOLEAN"},
+		},
+		{name: "ErrorInWhileCondition", src: "while (x) {}", want: &object.Error{Message: "identifier not found: x"}},
+		{
+			name: "BreakStopsLoop",
+			src:  "let i = 0; while (true) { if (i == 3) { break; } i = i + 1; } i",
+			want: &object.Integer{Value: 3},
+		},
+		{
+			name: "ContinueSkipsRestOfBody",
+			src:  "let i = 0; let sum = 0; while (i < 5) { i = i + 1; if (i == 2) { continue; } sum = sum + i; } sum",
+			want: &object.Integer{Value: 13},
+		},
+		{
+			name: "BreakStopsInnermostLoop",
+			src:  "let i = 0; let n = 0; while (i < 3) { i = i + 1; while (true) { n = n + 1; break; } } n",
+			want: &object.Integer{Value: 3},
+		},
+		{
+			name: "LabeledBreakStopsOuterLoop",
+			src:  "let i = 0; outer: while (true) { while (true) { i = i + 1; if (i == 3) { break outer; } } } i",
+			want: &object.Integer{Value: 3},
+		},
+		{
+			name: "LabeledContinueContinuesOuterLoop",
+			src: "let i = 0; let n = 0; outer: while (i < 3) { i = i + 1; while (true) { continue outer; } n = n + 1; } " +
+				"[i, n]",
+			want: &object.Array{Elements: []object.Object{&object.Integer{Value: 3}, &object.Integer{Value: 0}}},
+		},
+		{name: "BreakOutsideLoop", src: "break", want: &object.Error{Message: "break outside of a loop"}},
+		{name: "ContinueOutsideLoop", src: "continue", want: &object.Error{Message: "continue outside of a loop"}},
+		{
+			name: "BreakToUnknownLabel",
+			src:  "outer: while (true) { break inner; }",
+			want: &object.Error{Message: "break to unknown label: inner"},
+		},
+		{
+			name: "ContinueToUnknownLabel",
+			src:  "while (true) { continue outer; }",
+			want: &object.Error{Message: "continue to unknown label: outer"},
+		},
+		{
+			name: "BreakInFunctionInsideLoop",
+			src:  "while (true) { fn() { break; }() }",
+			want: &object.Error{Message: "break outside of a loop"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := eval(t, tc.src)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestEvalWithBigIntegers(t *testing.T) {
+	factorial := "let fact = fn(n) { if (n == 0) { 1 } else { n * fact(n - 1) } }; "
+	testCases := []struct {
+		name        string
+		src         string
+		bigIntegers bool
+		want        object.Object
+	}{
+		{
+			name:        "OverflowingFactorial",
+			src:         factorial + "fact(25)",
+			bigIntegers: true,
+			want:        bigInteger("15511210043330985984000000"),
+		},
+		{
+			name: "OverflowingFactorialWithoutOption",
+			src:  factorial + "fact(25)",
+			want: &object.Integer{Value: 7034535277573963776},
+		},
+		{
+			name: "NonOverflowingFactorial",
+			src:  factorial + "fact(20)",
+			want: &object.Integer{Value: 2432902008176640000},
+		},
+		{
+			name:        "NonOverflowingFactorialWithOption",
+			src:         factorial + "fact(20)",
+			bigIntegers: true,
+			want:        &object.Integer{Value: 2432902008176640000},
+		},
+		{
+			name:        "AdditionOverflow",
+			src:         "9223372036854775807 + 1",
+			bigIntegers: true,
+			want:        bigInteger("9223372036854775808"),
+		},
+		{
+			name:        "SubtractionOverflow",
+			src:         "-9223372036854775807 - 2",
+			bigIntegers: true,
+			want:        bigInteger("-9223372036854775809"),
+		},
+		{
+			name:        "DivisionOverflow",
+			src:         "(-9223372036854775807 - 1) / -1",
+			bigIntegers: true,
+			want:        bigInteger("9223372036854775808"),
+		},
+		{
+			name:        "NegationOverflow",
+			src:         "-(-9223372036854775807 - 1)",
+			bigIntegers: true,
+			want:        bigInteger("9223372036854775808"),
+		},
+		{
+			name:        "ResultWhichFitsIsInteger",
+			src:         "(9223372036854775807 + 1) - 1",
+			bigIntegers: true,
+			want:        &object.Integer{Value: 9223372036854775807},
+		},
+		{
+			name:        "ResultWhichFitsCanBeUsedAsIndex",
+			src:         "[1, 2][(9223372036854775807 + 1) - 9223372036854775807]",
+			bigIntegers: true,
+			want:        &object.Integer{Value: 2},
+		},
+		{
+			name:        "BigIntegerArithmetic",
+			src:         "let big = 9223372036854775807 * 10; big * big",
+			bigIntegers: true,
+			want:        bigInteger("8507059173023461584739690778423250124900"),
+		},
+		{
+			name:        "BigIntegerRemainder",
+			src:         "let big = 9223372036854775807 * 10; big * big % 1000",
+			bigIntegers: true,
+			want:        &object.Integer{Value: 900},
+		},
+		{
+			name:        "MixedComparison",
+			src:         "9223372036854775807 + 1 > 9223372036854775807",
+			bigIntegers: true,
+			want:        &object.Boolean{Value: true},
+		},
+		{
+			name:        "MixedEquality",
+			src:         "9223372036854775807 + 1 == 9223372036854775807",
+			bigIntegers: true,
+			want:        &object.Boolean{Value: false},
+		},
+		{
+			name:        "BigIntegerEquality",
+			src:         "9223372036854775807 + 1 == 9223372036854775807 + 1",
+			bigIntegers: true,
+			want:        &object.Boolean{Value: true},
+		},
+		{
+			name:        "DivisionByZero",
+			src:         "(9223372036854775807 + 1) / 0",
+			bigIntegers: true,
+			want:        &object.Error{Message: "division by zero"},
+		},
+		{
+			name:        "TypeMismatch",
+			src:         `9223372036854775807 + 1 + "a"`,
+			bigIntegers: true,
+			want:        &object.Error{Message: "type mismatch: BIG_INTEGER + STRING"},
+		},
+		{
+			name:        "Type",
+			src:         "type(9223372036854775807 + 1)",
+			bigIntegers: true,
+			want:        &object.String{Value: "BIG_INTEGER"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []evaluator.Option
+			if tc.bigIntegers {
+				opts = append(opts, evaluator.WithBigIntegers())
+			}
+			e := evaluator.New(&bytes.Buffer{}, strings.NewReader(""), opts...)
+
+			got := e.Eval(parse(t, tc.src), object.NewEnvironment())
+
+			if diff := cmp.Diff(tc.want, got, equateBigInts); diff != "" {
+				t.Fatalf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+// equateBigInts is a [cmp.Option] which compares [big.Int] values numerically.
+var equateBigInts = cmp.Comparer(func(a, b *big.Int) bool {
+	return a.Cmp(b) == 0
+})
+
+// bigInteger returns a big integer with the value of the given decimal string.
+func bigInteger(s string) *object.BigInteger {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big integer " + s)
+	}
+	return &object.BigInteger{Value: value}
+}
+
+func TestEvalTruthiness(t *testing.T) {
+	testCases := []struct {
+		name   string
+		value  string
+		truthy bool
+	}{
+		{name: "True", value: "true", truthy: true},
+		{name: "False", value: "false", truthy: false},
+		{name: "Null", value: "fn() {}()", truthy: false},
+		{name: "Zero", value: "0", truthy: true},
+		{name: "NonZeroInteger", value: "-1", truthy: true},
+		{name: "EmptyString", value: `""`, truthy: true},
+		{name: "EmptyArray", value: "[]", truthy: true},
+		{name: "EmptyHash", value: "{}", truthy: true},
+		{name: "Function", value: "fn() {}", truthy: true},
+		{name: "Builtin", value: "len", truthy: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, format := range []string{
+				"if (%s) { true } else { false }",
+				"!!(%s)",
+				"(%s) && true",
+				"(%s) || false",
+				"let truthy = false; while (%s) { truthy = true; return truthy; } truthy",
+			} {
+				src := fmt.Sprintf(format, tc.value)
+				got := eval(t, src)
+				want := &object.Boolean{Value: tc.truthy}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestEvalTruthinessOfValuesFromEnvironment(t *testing.T) {
+	src := "if (f) { 1 } else { if (n) { 2 } else { 3 } }"
+	env := object.NewEnvironment()
+	env.Set("f", &object.Boolean{Value: false})
+	env.Set("n", &object.Null{})
+	want := &object.Integer{Value: 3}
+
+	got := evaluator.Eval(parse(t, src), env)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestEvalEqualityOfBooleansFromEnvironment(t *testing.T) {
+	src := "[t == true, t != true, f == false, f == t, f != t]"
+	env := object.NewEnvironment()
+	env.Set("t", &object.Boolean{Value: true})
+	env.Set("f", &object.Boolean{Value: false})
+	want := &object.Array{Elements: []object.Object{
+		&object.Boolean{Value: true},
+		&object.Boolean{Value: false},
+		&object.Boolean{Value: true},
+		&object.Boolean{Value: false},
+		&object.Boolean{Value: true},
+	}}
+
+	got := evaluator.Eval(parse(t, src), env)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Eval() returned incorrect object for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestEvalEqualityMatchesEquals(t *testing.T) {
+	srcs := []string{
+		"1", "1", "2", `"a"`, `"a"`, `"b"`, "true", "true", "false", "fn() {}()", "[]", "[1, [2]]", "[1, [2]]", "[[2], 1]",
+		"{}", `{"a": 1, "b": 2}`, `{"b": 2, "a": 1}`, `{"a": 2}`, "fn(x) { x }", "fn(x) { x }", "len", "puts",
+	}
+	values := make([]object.Object, 0, len(srcs)+2)
+	for _, src := range srcs {
+		values = append(values, eval(t, src))
+	}
+	values = append(values, bigInteger("123456789012345678901234567890"), bigInteger("123456789012345678901234567890"))
+
+	for _, a := range values {
+		for _, b := range values {
+			env := object.NewEnvironment()
+			env.Set("a", a)
+			env.Set("b", b)
+			equal := object.Equals(a, b)
+
+			for _, operator := range []string{"==", "!="} {
+				src := fmt.Sprintf("a %s b", operator)
+				want := &object.Boolean{Value: equal == (operator == "==")}
+
+				got := evaluator.Eval(parse(t, src), env)
+
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("Eval() returned incorrect object for source %q with a = %s (%s) and b = %s (%s)\n"+
+						"diff:\n--- want\n+++ got\n%s", src, a.Inspect(), a.Type(), b.Inspect(), b.Type(), diff)
+				}
+			}
+		}
+	}
+}
+
+func TestEvalFunctionLiteral(t *testing.T) {
+	src := "fn(x) { x + 2; }"
+
+	got := eval(t, src)
+
+	function, ok := got.(*object.Function)
+	if !ok {
+		t.Fatalf("Eval() returned %T for source %q, want *object.Function", got, src)
+	}
+	want := "fn(x) { (x + 2); }"
+	if got := function.Inspect(); got != want {
+		t.Fatalf("Inspect() = %q for source %q, want %q", got, src, want)
+	}
+}
+
+// eval parses and evaluates the given source, failing the test if it can't be parsed.
+func eval(t *testing.T, src string) object.Object {
+	t.Helper()
+	return evaluator.Eval(parse(t, src), object.NewEnvironment())
+}
+
+// parse parses the given source, failing the test if it can't be parsed.
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", src, errs)
+	}
+	return program
+}
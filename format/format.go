@@ -0,0 +1,316 @@
+// Package format implements the canonical formatting of Monkey source code.
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/parser"
+	"github.com/marcuscaisey/monkey/token"
+)
+
+// indent is the indentation of each level of nested block statements.
+const indent = "    "
+
+// Source formats the given Monkey source code. Each statement is placed on its own line and terminated with a
+// semicolon, except for while statements which end at the closing brace of their body. The bodies of block statements
+// are indented by 4 spaces, infix operators are surrounded by single spaces, and the result ends in a newline.
+// Parentheses are only kept where they're needed to preserve the structure of expressions. Formatting is idempotent, so
+// formatting the result again returns it unchanged.
+//
+// Comments are preserved. A comment which follows a statement on the same line stays at the end of that statement's
+// line, separated from it by a single space, and every other comment is placed on its own line at the indentation of
+// the statement which follows it. An error is returned if the source can't be parsed.
+func Source(src string) (string, error) {
+	l := lexer.New(src)
+	l.EmitComments()
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		return "", fmt.Errorf("parsing source: %s", strings.Join(msgs, "\n"))
+	}
+	f := &formatter{comments: program.Comments}
+	// the zero token is used as the end of the source, which every remaining comment comes before
+	f.statements(program.Statements, token.Token{})
+	return f.String(), nil
+}
+
+// precedence is the precedence of an expression. Expressions with higher precedence bind more tightly to their
+// operands. These match the precedences used by the [parser] package.
+type precedence int
+
+const (
+	_ precedence = iota
+	lowest
+	logicalOr
+	logicalAnd
+	equals
+	lessGreater
+	sum
+	product
+	prefix
+	call
+)
+
+// infixPrecedences maps infix operators to their precedence.
+var infixPrecedences = map[string]precedence{
+	"||": logicalOr,
+	"&&": logicalAnd,
+	"==": equals,
+	"!=": equals,
+	"<":  lessGreater,
+	">":  lessGreater,
+	"<=": lessGreater,
+	">=": lessGreater,
+	"+":  sum,
+	"-":  sum,
+	"*":  product,
+	"/":  product,
+	"%":  product,
+}
+
+// formatter writes formatted statements and expressions to its strings.Builder. comments are the comments from the
+// source which haven't been written yet, in the order that they appear.
+type formatter struct {
+	strings.Builder
+	depth    int
+	comments []ast.Comment
+}
+
+// statements writes each of the statements on its own line at the current indentation, along with the comments which
+// appear before end in the source, where end is the token which follows the statements.
+func (f *formatter) statements(stmts []ast.Statement, end token.Token) {
+	for i, stmt := range stmts {
+		f.ownLineComments(firstToken(stmt))
+		f.statement(stmt)
+		next := end
+		if i+1 < len(stmts) {
+			next = firstToken(stmts[i+1])
+		}
+		f.endLine(next)
+	}
+	f.ownLineComments(end)
+}
+
+// endLine ends the current line, writing the comments which appear before the given token in the source. Trailing
+// comments are written at the end of the current line and any other comments are written on their own lines at the
+// current indentation.
+func (f *formatter) endLine(next token.Token) {
+	onLine := true
+	for len(f.comments) > 0 && before(f.comments[0], next) {
+		comment := f.comments[0]
+		f.comments = f.comments[1:]
+		if comment.Trailing && onLine {
+			f.WriteString(" " + comment.String())
+			continue
+		}
+		f.WriteString("\n" + strings.Repeat(indent, f.depth) + comment.String())
+		onLine = false
+	}
+	f.WriteString("\n")
+}
+
+// ownLineComments writes the comments which appear before the given token in the source, each on its own line at the
+// current indentation.
+func (f *formatter) ownLineComments(next token.Token) {
+	for len(f.comments) > 0 && before(f.comments[0], next) {
+		f.WriteString(strings.Repeat(indent, f.depth) + f.comments[0].String() + "\n")
+		f.comments = f.comments[1:]
+	}
+}
+
+// statement writes the statement at the current indentation, without ending the line.
+func (f *formatter) statement(stmt ast.Statement) {
+	f.WriteString(strings.Repeat(indent, f.depth))
+	switch stmt := stmt.(type) {
+	case ast.LetStatement:
+		f.WriteString("let " + stmt.Name.Value + " = ")
+		f.expression(stmt.Value, lowest)
+	case ast.AssignStatement:
+		f.WriteString(stmt.Name.Value + " = ")
+		f.expression(stmt.Value, lowest)
+	case ast.ReturnStatement:
+		f.WriteString("return")
+		if stmt.ReturnValue != nil {
+			f.WriteString(" ")
+			f.expression(stmt.ReturnValue, lowest)
+		}
+	case ast.ExpressionStatement:
+		f.expression(stmt.Expression, lowest)
+	case ast.BreakStatement:
+		f.WriteString("break")
+		if stmt.Label != "" {
+			f.WriteString(" " + stmt.Label)
+		}
+	case ast.ContinueStatement:
+		f.WriteString("continue")
+		if stmt.Label != "" {
+			f.WriteString(" " + stmt.Label)
+		}
+	case ast.WhileStatement:
+		if stmt.Label != "" {
+			f.WriteString(stmt.Label + ": ")
+		}
+		f.WriteString("while (")
+		f.expression(stmt.Condition, lowest)
+		f.WriteString(") ")
+		f.block(stmt.Body)
+		return
+	case ast.BlockStatement:
+		f.block(&stmt)
+		return
+	default:
+		panic(fmt.Sprintf("format: unexpected statement type %T", stmt))
+	}
+	f.WriteString(";")
+}
+
+// block writes the block statement, with each of its statements on its own line indented one level further than the
+// current indentation. Blocks which contain no statements or comments are written as {}.
+func (f *formatter) block(block *ast.BlockStatement) {
+	if len(block.Statements) == 0 && (len(f.comments) == 0 || !before(f.comments[0], block.RBrace)) {
+		f.WriteString("{}")
+		return
+	}
+	f.WriteString("{")
+	f.depth++
+	first := block.RBrace
+	if len(block.Statements) > 0 {
+		first = firstToken(block.Statements[0])
+	}
+	f.endLine(first)
+	f.statements(block.Statements, block.RBrace)
+	f.depth--
+	f.WriteString(strings.Repeat(indent, f.depth) + "}")
+}
+
+// expression writes the expression, wrapping it in parentheses if its precedence is lower than minPrecedence.
+func (f *formatter) expression(expr ast.Expression, minPrecedence precedence) {
+	if exprPrecedence(expr) < minPrecedence {
+		f.WriteString("(")
+		defer f.WriteString(")")
+	}
+
+	switch expr := expr.(type) {
+	case ast.IntegerLiteral:
+		// the literal is written as it appears in the source so that the base it was written in is preserved
+		f.WriteString(expr.Token.Literal)
+	case ast.Identifier, ast.StringLiteral, ast.Boolean:
+		f.WriteString(expr.String())
+	case ast.PrefixExpression:
+		f.WriteString(expr.Operator)
+		f.expression(expr.Right, prefix)
+	case ast.InfixExpression:
+		opPrecedence := infixPrecedences[expr.Operator]
+		f.expression(expr.Left, opPrecedence)
+		f.WriteString(" " + expr.Operator + " ")
+		// infix operators are left associative, so an operand on the right with the same precedence needs parentheses
+		f.expression(expr.Right, opPrecedence+1)
+	case ast.IfExpression:
+		f.WriteString("if (")
+		f.expression(expr.Condition, lowest)
+		f.WriteString(") ")
+		f.block(expr.Consequence)
+		if expr.Alternative != nil {
+			f.WriteString(" else ")
+			f.block(expr.Alternative)
+		}
+	case ast.FunctionLiteral:
+		params := make([]string, len(expr.Parameters))
+		for i, param := range expr.Parameters {
+			params[i] = param.Value
+		}
+		f.WriteString("fn(" + strings.Join(params, ", ") + ") ")
+		f.block(expr.Body)
+	case ast.CallExpression:
+		f.expression(expr.Function, call)
+		f.WriteString("(")
+		f.expressionList(expr.Arguments)
+		f.WriteString(")")
+	case ast.ArrayLiteral:
+		f.WriteString("[")
+		f.expressionList(expr.Elements)
+		f.WriteString("]")
+	case ast.IndexExpression:
+		f.expression(expr.Left, call)
+		f.WriteString("[")
+		f.expression(expr.Index, lowest)
+		f.WriteString("]")
+	case ast.HashLiteral:
+		f.WriteString("{")
+		for i, pair := range expr.Pairs {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.expression(pair.Key, lowest)
+			f.WriteString(": ")
+			f.expression(pair.Value, lowest)
+		}
+		f.WriteString("}")
+	default:
+		panic(fmt.Sprintf("format: unexpected expression type %T", expr))
+	}
+}
+
+// expressionList writes the expressions separated by commas.
+func (f *formatter) expressionList(exprs []ast.Expression) {
+	for i, expr := range exprs {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.expression(expr, lowest)
+	}
+}
+
+// firstToken returns the first token of the statement in the source.
+func firstToken(stmt ast.Statement) token.Token {
+	switch stmt := stmt.(type) {
+	case ast.LetStatement:
+		return stmt.Token
+	case ast.AssignStatement:
+		// the token of an assignment is its operator, which follows the name being assigned to
+		return stmt.Name.Token
+	case ast.ReturnStatement:
+		return stmt.Token
+	case ast.ExpressionStatement:
+		return stmt.Token
+	case ast.BreakStatement:
+		return stmt.Token
+	case ast.ContinueStatement:
+		return stmt.Token
+	case ast.WhileStatement:
+		return stmt.Token
+	case ast.BlockStatement:
+		return stmt.Token
+	default:
+		panic(fmt.Sprintf("format: unexpected statement type %T", stmt))
+	}
+}
+
+// before returns whether the comment appears before the given token in the source. Every comment appears before the
+// zero token, which is used to represent the end of the source.
+func before(comment ast.Comment, tok token.Token) bool {
+	if tok == (token.Token{}) {
+		return true
+	}
+	return comment.Token.Line < tok.Line || comment.Token.Line == tok.Line && comment.Token.Column < tok.Column
+}
+
+// exprPrecedence returns the precedence of the operator at the root of the expression. Expressions without an
+// operator, like literals, bind as tightly as possible.
+func exprPrecedence(expr ast.Expression) precedence {
+	switch expr := expr.(type) {
+	case ast.InfixExpression:
+		return infixPrecedences[expr.Operator]
+	case ast.PrefixExpression:
+		return prefix
+	default:
+		return call
+	}
+}
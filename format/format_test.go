@@ -0,0 +1,153 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/format"
+)
+
+func TestSource(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "Statements",
+			src:  "let   x=5 ;x=x+1\nreturn x;return",
+			want: "let x = 5;\nx = x + 1;\nreturn x;\nreturn;\n",
+		},
+		{
+			name: "RedundantParentheses",
+			src:  "((a+b))*c+(d*e)-(f-g)+(-h)+!(i)",
+			want: "(a + b) * c + d * e - (f - g) + -h + !i;\n",
+		},
+		{
+			name: "LogicalAndComparisonOperators",
+			src:  "(a<b)==(c>=d)&&e||(f||g)",
+			want: "a < b == c >= d && e || (f || g);\n",
+		},
+		{
+			name: "PrefixOfInfix",
+			src:  "-(a+b)",
+			want: "-(a + b);\n",
+		},
+		{
+			name: "FunctionLiteralAndBlocks",
+			src:  "let max=fn(a,b){if(a>b){return a}else{b}};let noop=fn(){}",
+			want: `let max = fn(a, b) {
+    if (a > b) {
+        return a;
+    } else {
+        b;
+    };
+};
+let noop = fn() {};
+`,
+		},
+		{
+			name: "WhileStatement",
+			src:  "while(x<10){x=x+1}while(true){}",
+			want: "while (x < 10) {\n    x = x + 1;\n}\nwhile (true) {}\n",
+		},
+		{
+			name: "BreakAndContinueStatements",
+			src:  "outer:while(x){while(y){continue outer}break}",
+			want: "outer: while (x) {\n    while (y) {\n        continue outer;\n    }\n    break;\n}\n",
+		},
+		{
+			name: "CallAndIndexExpressions",
+			src:  "f( a,b ) (c)[ 1 ][arr[0]];(a+b)(c);(-a)[0]",
+			want: "f(a, b)(c)[1][arr[0]];\n(a + b)(c);\n(-a)[0];\n",
+		},
+		{
+			name: "Literals",
+			src:  "[1,0x10,true,`a\"b`,[ ]];{\"a\" :1,2:{}}",
+			want: "[1, 0x10, true, \"a\\\"b\", []];\n{\"a\": 1, 2: {}};\n",
+		},
+		{
+			name: "IntegerLiteralSpelling",
+			src:  "0xFF+0xdeadBEEF*10",
+			want: "0xFF + 0xdeadBEEF * 10;\n",
+		},
+		{
+			name: "DocAndTrailingCommentsRoundTrip",
+			src:  "// double returns twice x.\nlet double = fn(x) {\n    x * 2; // shifting would be faster\n};\n",
+			want: "// double returns twice x.\nlet double = fn(x) {\n    x * 2; // shifting would be faster\n};\n",
+		},
+		{
+			name: "CommentsArePreserved",
+			src:  "// comment\nlet x = 1; /* comment */",
+			want: "// comment\nlet x = 1; /* comment */\n",
+		},
+		{
+			name: "CommentsBetweenStatements",
+			src:  "a // first\n\n// between\n/* also\nbetween */b",
+			want: "a; // first\n// between\n/* also\nbetween */\nb;\n",
+		},
+		{
+			name: "CommentsInBlock",
+			src:  "let f=fn(x){ // start\n// before\nx+1 // sum\n// end\n}; // after\n// final",
+			want: "let f = fn(x) { // start\n    // before\n    x + 1; // sum\n    // end\n}; // after\n// final\n",
+		},
+		{
+			name: "CommentInEmptyBlock",
+			src:  "while(x){\n// todo\n}",
+			want: "while (x) {\n    // todo\n}\n",
+		},
+		{
+			name: "TrailingCommentAfterClosingBrace",
+			src:  "if(x){\ny\n} // done\nz",
+			want: "if (x) {\n    y;\n}; // done\nz;\n",
+		},
+		{
+			name: "TrailingCommentInsideExpressionMovesToEndOfStatement",
+			src:  "let x = [1, // one\n2];",
+			want: "let x = [1, 2]; // one\n",
+		},
+		{
+			name: "OnlyComments",
+			src:  "// a\n\n/* b */",
+			want: "// a\n/* b */\n",
+		},
+		{
+			name: "Empty",
+			src:  "",
+			want: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := format.Source(tc.src)
+			if err != nil {
+				t.Fatalf("Source(%q) returned unexpected error: %s", tc.src, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Source(%q) returned incorrect source\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+
+			again, err := format.Source(got)
+			if err != nil {
+				t.Fatalf("Source(%q) returned unexpected error: %s", got, err)
+			}
+			if diff := cmp.Diff(got, again); diff != "" {
+				t.Fatalf("Source(%q) changed already formatted source\ndiff:\n--- want\n+++ got\n%s", got, diff)
+			}
+		})
+	}
+}
+
+func TestSourceReturnsErrorForInvalidSource(t *testing.T) {
+	src := "let = 5;"
+	want := `parsing source: 1:5: expected IDENT, got ASSIGN("=")`
+	_, err := format.Source(src)
+	if err == nil {
+		t.Fatalf("Source(%q) returned no error, want %q", src, want)
+	}
+	if got := err.Error(); got != want {
+		t.Fatalf("Source(%q) returned error %q, want %q", src, got, want)
+	}
+}
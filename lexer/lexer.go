@@ -3,107 +3,443 @@
 package lexer
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/marcuscaisey/monkey/token"
 )
 
-// Lexer parses Monkey source code containing only ASCII characters.
+// readChunkSize is the number of bytes that a Lexer created with [NewReader] attempts to read at a time.
+const readChunkSize = 4096
+
+// Lexer parses UTF-8 encoded Monkey source code.
 type Lexer struct {
 	src         string
 	eofReturned bool
 	pos         int
+	// r is the reader that src is read from incrementally, or nil if the source has been read in full. readBuf is the
+	// buffer used to read from r and readErr is the first error other than io.EOF returned by r.
+	r       io.Reader
+	readBuf []byte
+	readErr error
+	// peeked is whether the next token has been read by PeekToken, in which case it's stored in peekedToken and
+	// peekedErr.
+	peeked      bool
+	peekedToken token.Token
+	peekedErr   error
+	// collectErrors is whether errors for malformed tokens should be recorded in errors instead of being returned.
+	collectErrors bool
+	errors        []error
+	// emitComments is whether comments should be returned as tokens instead of being skipped.
+	emitComments bool
+	// line and column are the 1-based line and column numbers of the current position. column counts runes, not bytes.
+	line   int
+	column int
+	// tokenStart, tokenLine, and tokenColumn record where in the source the token currently being read starts.
+	tokenStart  int
+	tokenLine   int
+	tokenColumn int
 }
 
 // New initialises a new Lexer with the given source code.
 func New(src string) *Lexer {
 	return &Lexer{
-		src: src,
+		src:    src,
+		line:   1,
+		column: 1,
+	}
+}
+
+// NewReader initialises a new Lexer which reads source code incrementally from the given [io.Reader]. Only source which
+// hasn't yet been turned into tokens is held in memory, so this can be used for large sources which would be expensive
+// to read in full.
+func NewReader(r io.Reader) *Lexer {
+	return &Lexer{
+		r:       r,
+		readBuf: make([]byte, readChunkSize),
+		line:    1,
+		column:  1,
 	}
 }
 
+// Reset discards all of the Lexer's state and reinitialises it with the given source code, as if it had just been
+// created with [New]. This allows a Lexer to be reused for lexing many sources.
+func (l *Lexer) Reset(src string) {
+	*l = Lexer{
+		src:    src,
+		line:   1,
+		column: 1,
+	}
+}
+
+// CollectErrors switches the Lexer into a mode where a malformed token doesn't cause [Lexer.NextToken] to return an
+// error. Instead, a token of type [token.Illegal] containing the malformed source is returned and the error is recorded
+// so that it can be retrieved later with [Lexer.Errors]. This allows all of the errors in some source code to be found
+// in one pass. Errors from the reader of a Lexer created with [NewReader] are still returned.
+func (l *Lexer) CollectErrors() {
+	l.collectErrors = true
+}
+
+// Errors returns the errors which have been recorded since [Lexer.CollectErrors] was called, in the order that they
+// were encountered.
+func (l *Lexer) Errors() []error {
+	return l.errors
+}
+
+// EmitComments switches the Lexer into a mode where comments are returned by [Lexer.NextToken] as tokens of type
+// [token.Comment] instead of being skipped. The literal of each comment token is the source of the comment, including
+// the // or /* and */ which delimit it and excluding the newline which ends a line comment.
+func (l *Lexer) EmitComments() {
+	l.emitComments = true
+}
+
 // NextToken returns the next token from the source code.
 // Calling repeatedly will return all of the tokens, ending with a token of type [token.EOF]. Calls after this will
-// always return a [token.EOF].
-func (l *Lexer) NextToken() token.Token {
-	l.consumeWhitespace()
+// always return a [token.EOF]. If the next token is malformed, then a non-nil error is returned describing why. If the
+// Lexer was created with [NewReader] and reading from the reader fails, then the read error is returned.
+func (l *Lexer) NextToken() (token.Token, error) {
+	if l.peeked {
+		l.peeked = false
+		return l.peekedToken, l.peekedErr
+	}
+	return l.readToken()
+}
+
+// Tokens starts lexing the source code in a new goroutine and returns a channel which each token is sent on, ending
+// with the [token.EOF] token, after which the channel is closed. Lexing stops early and the channel is closed if the
+// given context is cancelled, so the goroutine doesn't leak if the receiver stops reading.
+//
+// Tokens calls [Lexer.CollectErrors] so that a malformed token is sent as a [token.Illegal] token and its error can be
+// retrieved with [Lexer.Errors] once the channel is closed. If the Lexer was created with [NewReader] and reading from
+// the reader fails, then a [token.Illegal] token whose literal is the read error's message is sent last. The Lexer
+// shouldn't be used by the caller until the channel is closed.
+func (l *Lexer) Tokens(ctx context.Context) <-chan token.Token {
+	l.CollectErrors()
+	tokens := make(chan token.Token)
+	go func() {
+		defer close(tokens)
+		for {
+			tok, err := l.NextToken()
+			if err != nil {
+				tok = token.Token{Type: token.Illegal, Literal: err.Error(), Line: l.line, Column: l.column}
+			}
+			select {
+			case tokens <- tok:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || tok.Type == token.EOF {
+				return
+			}
+		}
+	}()
+	return tokens
+}
+
+// PeekToken returns the same token and error as [Lexer.NextToken] but doesn't advance the Lexer, so the following call
+// to NextToken will return them again.
+func (l *Lexer) PeekToken() (token.Token, error) {
+	if !l.peeked {
+		l.peekedToken, l.peekedErr = l.readToken()
+		l.peeked = true
+	}
+	return l.peekedToken, l.peekedErr
+}
+
+// readToken reads the next token from the source.
+func (l *Lexer) readToken() (token.Token, error) {
+	if l.r != nil {
+		// discard the source which has already been turned into tokens
+		l.src = l.src[l.pos:]
+		l.pos = 0
+	}
+	tok, err := l.scanToken()
+	if l.readErr != nil {
+		return token.Token{}, l.readErr
+	}
+	if err != nil && l.collectErrors {
+		l.errors = append(l.errors, err)
+		return l.newToken(token.Illegal, l.src[l.tokenStart:l.pos]), nil
+	}
+	return tok, err
+}
+
+// scanToken consumes the characters making up the next token and returns it.
+func (l *Lexer) scanToken() (token.Token, error) {
+	if err := l.consumeWhitespaceAndComments(); err != nil {
+		return token.Token{}, err
+	}
+	if l.emitComments && l.peekChar() == '/' {
+		switch l.peekNextChar() {
+		case '/':
+			l.startToken()
+			l.consumeLineComment()
+			return l.newToken(token.Comment, l.src[l.tokenStart:l.pos]), nil
+		case '*':
+			if err := l.consumeBlockComment(); err != nil {
+				return token.Token{}, err
+			}
+			return l.newToken(token.Comment, l.src[l.tokenStart:l.pos]), nil
+		}
+	}
+	l.startToken()
 	switch char := l.readChar(); char {
 	case 0:
-		return newToken(token.EOF, "")
+		return l.newToken(token.EOF, ""), nil
 	case '=':
 		if l.peekChar() == '=' {
 			l.readChar()
-			return newToken(token.Equal, "==")
+			return l.newToken(token.Equal, "=="), nil
 		}
-		return newToken(token.Assign, string(char))
+		return l.newToken(token.Assign, string(char)), nil
 	case '+':
-		return newToken(token.Plus, string(char))
+		if l.peekChar() == '=' {
+			l.readChar()
+			return l.newToken(token.PlusAssign, "+="), nil
+		}
+		return l.newToken(token.Plus, string(char)), nil
 	case '-':
-		return newToken(token.Minus, string(char))
+		if l.peekChar() == '=' {
+			l.readChar()
+			return l.newToken(token.MinusAssign, "-="), nil
+		}
+		return l.newToken(token.Minus, string(char)), nil
 	case '/':
-		return newToken(token.Slash, string(char))
+		if l.peekChar() == '=' {
+			l.readChar()
+			return l.newToken(token.SlashAssign, "/="), nil
+		}
+		return l.newToken(token.Slash, string(char)), nil
 	case '*':
-		return newToken(token.Asterisk, string(char))
+		if l.peekChar() == '=' {
+			l.readChar()
+			return l.newToken(token.AsteriskAssign, "*="), nil
+		}
+		return l.newToken(token.Asterisk, string(char)), nil
+	case '%':
+		return l.newToken(token.Percent, string(char)), nil
 	case '!':
 		if l.peekChar() == '=' {
 			l.readChar()
-			return newToken(token.NotEqual, "!=")
+			return l.newToken(token.NotEqual, "!="), nil
 		}
-		return newToken(token.Bang, string(char))
+		return l.newToken(token.Bang, string(char)), nil
 	case '<':
-		return newToken(token.Less, string(char))
+		if l.peekChar() == '=' {
+			l.readChar()
+			return l.newToken(token.LessEqual, "<="), nil
+		}
+		return l.newToken(token.Less, string(char)), nil
 	case '>':
-		return newToken(token.Greater, string(char))
+		if l.peekChar() == '=' {
+			l.readChar()
+			return l.newToken(token.GreaterEqual, ">="), nil
+		}
+		return l.newToken(token.Greater, string(char)), nil
+	case '&':
+		if l.peekChar() == '&' {
+			l.readChar()
+			return l.newToken(token.And, "&&"), nil
+		}
+		return l.newToken(token.Illegal, string(char)), nil
+	case '|':
+		if l.peekChar() == '|' {
+			l.readChar()
+			return l.newToken(token.Or, "||"), nil
+		}
+		return l.newToken(token.Illegal, string(char)), nil
 	case ',':
-		return newToken(token.Comma, string(char))
+		return l.newToken(token.Comma, string(char)), nil
 	case ';':
-		return newToken(token.Semicolon, string(char))
+		return l.newToken(token.Semicolon, string(char)), nil
+	case ':':
+		return l.newToken(token.Colon, string(char)), nil
+	case '.':
+		return l.newToken(token.Dot, string(char)), nil
 	case '(':
-		return newToken(token.LParen, string(char))
+		return l.newToken(token.LParen, string(char)), nil
 	case ')':
-		return newToken(token.RParen, string(char))
+		return l.newToken(token.RParen, string(char)), nil
 	case '{':
-		return newToken(token.LBrace, string(char))
+		return l.newToken(token.LBrace, string(char)), nil
 	case '}':
-		return newToken(token.RBrace, string(char))
+		return l.newToken(token.RBrace, string(char)), nil
+	case '[':
+		return l.newToken(token.LBracket, string(char)), nil
+	case ']':
+		return l.newToken(token.RBracket, string(char)), nil
+	case '"':
+		str, err := l.readString()
+		if err != nil {
+			return token.Token{}, err
+		}
+		return l.newToken(token.String, str), nil
+	case '`':
+		str, err := l.readRawString()
+		if err != nil {
+			return token.Token{}, err
+		}
+		return l.newToken(token.String, str), nil
+	case '\'':
+		char, err := l.readCharLiteral()
+		if err != nil {
+			return token.Token{}, err
+		}
+		return l.newToken(token.Char, char), nil
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		return newToken(token.Int, l.readCharsWhile(char, isNumber))
+		return l.readNumber(char)
 	default:
-		if isValidFirstIdentChar(char) {
-			ident := l.readCharsWhile(char, isValidIdentChar)
+		r, err := l.readRune(char, l.tokenLine, l.tokenColumn)
+		if err != nil {
+			return token.Token{}, err
+		}
+		if isValidFirstIdentRune(r) {
+			ident := l.readIdent()
 			tokenType := token.IdentTokenType(ident)
-			return newToken(tokenType, ident)
+			return l.newToken(tokenType, ident), nil
 		}
-		return newToken(token.Illegal, string(char))
+		return l.newToken(token.Illegal, string(r)), nil
 	}
 }
 
 // readChar consumes the character at the current position and returns it. If the end of the source has been reached, a
 // null character is returned.
 func (l *Lexer) readChar() byte {
+	l.fill(1)
 	if l.pos == len(l.src) {
 		return 0
 	}
 	char := l.src[l.pos]
 	l.pos++
+	if char == '\n' {
+		l.line++
+		l.column = 1
+	} else if utf8.RuneStart(char) {
+		// only count the first byte of each rune so that the column counts runes
+		l.column++
+	}
 	return char
 }
 
+// readRune decodes the rune whose first byte is the given character, which has already been consumed, and consumes the
+// rest of its bytes. line and column should be the position of the first byte. An error is returned if the bytes aren't
+// valid UTF-8.
+func (l *Lexer) readRune(firstChar byte, line int, column int) (rune, error) {
+	if firstChar < utf8.RuneSelf {
+		return rune(firstChar), nil
+	}
+	l.fill(utf8.UTFMax - 1)
+	r, size := utf8.DecodeRuneInString(l.src[l.pos-1:])
+	if r == utf8.RuneError && size == 1 {
+		return 0, &InvalidUTF8Error{Byte: firstChar, Line: line, Column: column}
+	}
+	for i := 1; i < size; i++ {
+		l.readChar()
+	}
+	return r, nil
+}
+
+// peekRune returns the rune at the current position but doesn't consume it. If the end of the source has been reached
+// or the bytes at the current position aren't valid UTF-8, then [utf8.RuneError] is returned.
+func (l *Lexer) peekRune() rune {
+	l.fill(utf8.UTFMax)
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return r
+}
+
 // peekChar returns the character at the current position but doesn't consume it.
 func (l *Lexer) peekChar() byte {
+	l.fill(1)
 	if l.pos == len(l.src) {
 		return 0
 	}
 	return l.src[l.pos]
 }
 
-// consumeWhitespace consumes the whitespace at the current position in the source.
-func (l *Lexer) consumeWhitespace() {
-	for isWhitespace(l.peekChar()) {
+// peekNextChar returns the character after the one at the current position but doesn't consume either of them.
+func (l *Lexer) peekNextChar() byte {
+	l.fill(2)
+	if l.pos+1 >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+1]
+}
+
+// startToken records the current position as the start of the token currently being read.
+func (l *Lexer) startToken() {
+	l.tokenStart = l.pos
+	l.tokenLine = l.line
+	l.tokenColumn = l.column
+}
+
+// fill reads from the Lexer's reader until at least n characters are available from the current position, or the
+// reader has nothing more to give. It does nothing if the Lexer wasn't created with [NewReader].
+func (l *Lexer) fill(n int) {
+	for l.r != nil && len(l.src)-l.pos < n {
+		read, err := l.r.Read(l.readBuf)
+		l.src += string(l.readBuf[:read])
+		if err != nil {
+			if err != io.EOF {
+				l.readErr = err
+			}
+			l.r = nil
+		}
+	}
+}
+
+// consumeWhitespaceAndComments consumes the whitespace and comments at the current position in the source. Comments
+// aren't consumed if the Lexer is emitting them, since they're returned as tokens instead.
+func (l *Lexer) consumeWhitespaceAndComments() error {
+	for {
+		switch {
+		case isWhitespace(l.peekChar()):
+			l.readChar()
+		case l.emitComments:
+			return nil
+		case l.peekChar() == '/' && l.peekNextChar() == '/':
+			l.consumeLineComment()
+		case l.peekChar() == '/' && l.peekNextChar() == '*':
+			if err := l.consumeBlockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// consumeLineComment consumes a comment which starts with // at the current position and continues until the end of the
+// line. The newline at the end of the comment isn't consumed.
+func (l *Lexer) consumeLineComment() {
+	for char := l.peekChar(); char != '\n' && char != 0; char = l.peekChar() {
 		l.readChar()
 	}
 }
 
+// consumeBlockComment consumes a comment which starts with /* at the current position and continues until the next */.
+// The comment is treated as the token currently being read so that the source of an unterminated comment can be
+// returned as a [token.Illegal] token when collecting errors.
+func (l *Lexer) consumeBlockComment() error {
+	l.startToken()
+	l.readChar()
+	l.readChar()
+	for {
+		switch l.readChar() {
+		case 0:
+			return &UnterminatedCommentError{Line: l.tokenLine, Column: l.tokenColumn}
+		case '*':
+			if l.peekChar() == '/' {
+				l.readChar()
+				return nil
+			}
+		}
+	}
+}
+
 func isWhitespace(char byte) bool {
 	switch char {
 	case '\t', '\n', '\v', '\f', '\r', ' ':
@@ -113,29 +449,323 @@ func isWhitespace(char byte) bool {
 	}
 }
 
-// readCharsWhile builds a string starting with the given character and then added to by consuming characters until
-// isValid returns false.
-func (l *Lexer) readCharsWhile(firstChar byte, isValid func(char byte) bool) string {
+// readString builds a string by consuming characters until a closing double quote is reached. Escape sequences are
+// replaced by the character that they represent. The opening double quote should already have been consumed. If an
+// invalid escape sequence is found, the rest of the string is still consumed so that lexing can continue after it.
+func (l *Lexer) readString() (string, error) {
 	b := strings.Builder{}
-	b.WriteByte(firstChar)
-	for isValid(l.peekChar()) {
-		b.WriteByte(l.readChar())
+	var escapeErr error
+	for {
+		switch char := l.readChar(); char {
+		case 0:
+			if escapeErr != nil {
+				return "", escapeErr
+			}
+			return "", &UnterminatedStringError{Line: l.tokenLine, Column: l.tokenColumn}
+		case '"':
+			if escapeErr != nil {
+				return "", escapeErr
+			}
+			return b.String(), nil
+		case '\\':
+			if l.peekChar() == 0 {
+				continue
+			}
+			escaped, err := l.readEscapeSequence('"')
+			if err != nil && escapeErr == nil {
+				escapeErr = err
+			}
+			b.WriteByte(escaped)
+		default:
+			b.WriteByte(char)
+		}
+	}
+}
+
+// readRawString builds a string by consuming characters until a closing backtick is reached. Unlike [Lexer.readString],
+// no escape sequences are replaced. The opening backtick should already have been consumed.
+func (l *Lexer) readRawString() (string, error) {
+	b := strings.Builder{}
+	for {
+		switch char := l.readChar(); char {
+		case 0:
+			return "", &UnterminatedStringError{Line: l.tokenLine, Column: l.tokenColumn}
+		case '`':
+			return b.String(), nil
+		default:
+			b.WriteByte(char)
+		}
+	}
+}
+
+// readCharLiteral reads the single character between the quotes of a character literal. The opening quote should
+// already have been consumed.
+func (l *Lexer) readCharLiteral() (string, error) {
+	var char string
+	switch firstChar := l.peekChar(); firstChar {
+	case 0, '\n':
+		return "", l.newInvalidCharError("unterminated character literal")
+	case '\'':
+		l.readChar()
+		return "", l.newInvalidCharError("empty character literal")
+	case '\\':
+		l.readChar()
+		if l.peekChar() == 0 {
+			return "", l.newInvalidCharError("unterminated character literal")
+		}
+		escaped, err := l.readEscapeSequence('\'')
+		if err != nil {
+			l.consumeRestOfCharLiteral()
+			return "", err
+		}
+		char = string(escaped)
+	default:
+		line, column := l.line, l.column
+		r, err := l.readRune(l.readChar(), line, column)
+		if err != nil {
+			return "", err
+		}
+		char = string(r)
+	}
+
+	switch l.peekChar() {
+	case '\'':
+		l.readChar()
+		return char, nil
+	case 0, '\n':
+		return "", l.newInvalidCharError("unterminated character literal")
+	default:
+		l.consumeRestOfCharLiteral()
+		return "", l.newInvalidCharError("more than one character in character literal")
+	}
+}
+
+// consumeRestOfCharLiteral consumes the rest of a malformed character literal up to and including the closing quote, so
+// that lexing can continue after it. A character literal can't span lines, so consumption also stops at the end of the
+// line.
+func (l *Lexer) consumeRestOfCharLiteral() {
+	for char := l.peekChar(); char != '\'' && char != '\n' && char != 0; char = l.peekChar() {
+		l.readChar()
+	}
+	if l.peekChar() == '\'' {
+		l.readChar()
+	}
+}
+
+// readEscapeSequence consumes the character following a backslash in a string or character literal which is delimited
+// by the given quote character, and returns the character that the escape sequence represents.
+func (l *Lexer) readEscapeSequence(quote byte) (byte, error) {
+	line, column := l.line, l.column
+	char := l.readChar()
+	if char == quote {
+		return quote, nil
+	}
+	escaped, ok := escapedChars[char]
+	if !ok {
+		return 0, &InvalidEscapeError{Char: char, Line: line, Column: column}
+	}
+	return escaped, nil
+}
+
+// escapedChars maps each character which can follow a backslash in a string or character literal to the character
+// that the escape sequence represents. The quote character which delimits the literal can also be escaped.
+var escapedChars = map[byte]byte{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'\\': '\\',
+}
+
+// readNumber reads an integer or float literal starting with the given digit.
+//
+// A leading 0x or 0X starts a hexadecimal integer literal, whose literal value retains the prefix. A '.' is only
+// treated as a decimal point if it's immediately followed by a digit, otherwise the number ends before it. This means
+// that 5. and 5.foo are both read as the integer 5, leaving the '.' to be read as a separate token.
+//
+// Digits can be separated by single underscores, which are stripped from the literal value. An underscore can't come
+// at the end of a number since it wouldn't be separating anything. An underscore can't come at the start of a number
+// either but this is never seen here since _5 is an identifier.
+func (l *Lexer) readNumber(firstDigit byte) (token.Token, error) {
+	tokenType := token.Int
+	if firstDigit == '0' && (l.peekChar() == 'x' || l.peekChar() == 'X') {
+		l.readChar()
+		if !isHexNumber(l.peekChar()) {
+			return token.Token{}, l.newInvalidNumberError("no digits after 0x prefix")
+		}
+		if err := l.consumeDigits(isHexNumber); err != nil {
+			return token.Token{}, err
+		}
+	} else {
+		if err := l.consumeDigits(isNumber); err != nil {
+			return token.Token{}, err
+		}
+		if l.peekChar() == '.' && isNumber(l.peekNextChar()) {
+			tokenType = token.Float
+			l.readChar()
+			if err := l.consumeDigits(isNumber); err != nil {
+				return token.Token{}, err
+			}
+		}
+	}
+	literal := l.src[l.tokenStart:l.pos]
+	if strings.IndexByte(literal, '_') != -1 {
+		literal = strings.ReplaceAll(literal, "_", "")
+	}
+	return l.newToken(tokenType, literal), nil
+}
+
+// consumeDigits consumes digits until isDigit returns false. The digits can be separated by single underscores.
+func (l *Lexer) consumeDigits(isDigit func(char byte) bool) error {
+	for {
+		switch char := l.peekChar(); {
+		case char == '_':
+			l.readChar()
+			if l.peekChar() == '_' {
+				for l.peekChar() == '_' {
+					l.readChar()
+				}
+				return l.newInvalidNumberError("consecutive underscores")
+			}
+			if !isDigit(l.peekChar()) {
+				return l.newInvalidNumberError("trailing underscore")
+			}
+		case isDigit(char):
+			l.readChar()
+		default:
+			return nil
+		}
 	}
-	return b.String()
 }
 
 func isNumber(char byte) bool {
 	return '0' <= char && char <= '9'
 }
 
-func isValidFirstIdentChar(c byte) bool {
-	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c == '_'
+func isHexNumber(char byte) bool {
+	return isNumber(char) || ('a' <= char && char <= 'f') || ('A' <= char && char <= 'F')
+}
+
+// readIdent reads an identifier whose first rune has already been consumed by consuming runes until one is reached
+// which isn't valid in an identifier.
+func (l *Lexer) readIdent() string {
+	for r := l.peekRune(); isValidIdentRune(r); r = l.peekRune() {
+		for i := 0; i < utf8.RuneLen(r); i++ {
+			l.readChar()
+		}
+	}
+	return l.src[l.tokenStart:l.pos]
+}
+
+func isValidFirstIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isValidIdentRune(r rune) bool {
+	return isValidFirstIdentRune(r) || unicode.IsDigit(r)
+}
+
+// newToken returns a token with the given type and literal, positioned at the start of the token currently being read.
+func (l *Lexer) newToken(tokenType token.TokenType, literal string) token.Token {
+	return token.Token{Type: tokenType, Literal: literal, Line: l.tokenLine, Column: l.tokenColumn}
+}
+
+// newInvalidCharError returns an [InvalidCharError] for the character literal currently being read.
+func (l *Lexer) newInvalidCharError(reason string) *InvalidCharError {
+	return &InvalidCharError{Reason: reason, Line: l.tokenLine, Column: l.tokenColumn}
+}
+
+// newInvalidNumberError returns an [InvalidNumberError] for the number literal currently being read, which has been
+// consumed up to the point where it was found to be malformed. The rest of the number is then consumed so that lexing
+// can continue after it.
+func (l *Lexer) newInvalidNumberError(reason string) *InvalidNumberError {
+	err := &InvalidNumberError{
+		Literal: l.src[l.tokenStart:l.pos],
+		Reason:  reason,
+		Line:    l.tokenLine,
+		Column:  l.tokenColumn,
+	}
+	for char := l.peekChar(); isHexNumber(char) || char == '_'; char = l.peekChar() {
+		l.readChar()
+	}
+	return err
+}
+
+// UnterminatedStringError is returned by [Lexer.NextToken] when the end of the source is reached before the closing
+// double quote or backtick of a string literal.
+type UnterminatedStringError struct {
+	// Line and Column are the position in the source of the opening double quote or backtick.
+	Line   int
+	Column int
+}
+
+func (e *UnterminatedStringError) Error() string {
+	return fmt.Sprintf("%d:%d: unterminated string literal", e.Line, e.Column)
+}
+
+// InvalidUTF8Error is returned by [Lexer.NextToken] when the source contains a byte which isn't part of a valid UTF-8
+// encoded rune.
+type InvalidUTF8Error struct {
+	// Byte is the first byte of the invalid UTF-8 sequence.
+	Byte byte
+	// Line and Column are the position in the source of Byte.
+	Line   int
+	Column int
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("%d:%d: invalid UTF-8 encoded byte 0x%02x", e.Line, e.Column, e.Byte)
+}
+
+// UnterminatedCommentError is returned by [Lexer.NextToken] when the end of the source is reached before the closing */
+// of a block comment.
+type UnterminatedCommentError struct {
+	// Line and Column are the position in the source of the opening /*.
+	Line   int
+	Column int
+}
+
+func (e *UnterminatedCommentError) Error() string {
+	return fmt.Sprintf("%d:%d: unterminated block comment", e.Line, e.Column)
+}
+
+// InvalidCharError is returned by [Lexer.NextToken] when a character literal is malformed.
+type InvalidCharError struct {
+	// Reason describes why the character literal is malformed.
+	Reason string
+	// Line and Column are the position in the source of the opening quote of the character literal.
+	Line   int
+	Column int
+}
+
+func (e *InvalidCharError) Error() string {
+	return fmt.Sprintf("%d:%d: invalid character literal: %s", e.Line, e.Column, e.Reason)
+}
+
+// InvalidEscapeError is returned by [Lexer.NextToken] when a string or character literal contains a backslash followed
+// by a character which doesn't form a valid escape sequence.
+type InvalidEscapeError struct {
+	// Char is the character following the backslash.
+	Char byte
+	// Line and Column are the position in the source of Char.
+	Line   int
+	Column int
+}
+
+func (e *InvalidEscapeError) Error() string {
+	return fmt.Sprintf("%d:%d: invalid escape sequence %q", e.Line, e.Column, `\`+string(e.Char))
 }
 
-func isValidIdentChar(c byte) bool {
-	return isValidFirstIdentChar(c) || ('0' <= c && c <= '9')
+// InvalidNumberError is returned by [Lexer.NextToken] when a number literal is malformed.
+type InvalidNumberError struct {
+	// Literal is the malformed part of the number literal.
+	Literal string
+	// Reason describes why the number literal is malformed.
+	Reason string
+	// Line and Column are the position in the source of the start of the number literal.
+	Line   int
+	Column int
 }
 
-func newToken(tokenType token.TokenType, literal string) token.Token {
-	return token.Token{Type: tokenType, Literal: literal}
+func (e *InvalidNumberError) Error() string {
+	return fmt.Sprintf("%d:%d: invalid number literal %q: %s", e.Line, e.Column, e.Literal, e.Reason)
 }
@@ -1,10 +1,17 @@
 package lexer_test
 
 import (
+	"context"
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 	"unicode"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/marcuscaisey/monkey/lexer"
 	"github.com/marcuscaisey/monkey/token"
@@ -27,7 +34,7 @@ let add = fn(x, y) {
 
 let result = add(five, ten);
 
-!-/*5;
+!-/ *5;
 
 5 < 10 > 5;
 
@@ -116,6 +123,190 @@ if (5 < 10) {
 				{Type: token.Semicolon, Literal: ";"},
 			},
 		},
+		{
+			name: "ParsesModuloOperator",
+			src:  "5 % 2",
+			want: []token.Token{
+				{Type: token.Int, Literal: "5"},
+				{Type: token.Percent, Literal: "%"},
+				{Type: token.Int, Literal: "2"},
+			},
+		},
+		{
+			name: "ParsesComparisonOperators",
+			src:  "a <= b; a >= b; a < =b",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "a"},
+				{Type: token.LessEqual, Literal: "<="},
+				{Type: token.Ident, Literal: "b"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "a"},
+				{Type: token.GreaterEqual, Literal: ">="},
+				{Type: token.Ident, Literal: "b"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "a"},
+				{Type: token.Less, Literal: "<"},
+				{Type: token.Assign, Literal: "="},
+				{Type: token.Ident, Literal: "b"},
+			},
+		},
+		{
+			name: "ParsesLogicalOperators",
+			src:  "true && false; a || b",
+			want: []token.Token{
+				{Type: token.True, Literal: "true"},
+				{Type: token.And, Literal: "&&"},
+				{Type: token.False, Literal: "false"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "a"},
+				{Type: token.Or, Literal: "||"},
+				{Type: token.Ident, Literal: "b"},
+			},
+		},
+		{
+			name: "ReturnsIllegalTokenTypeForSingleAmpersandAndPipe",
+			src:  "a & b | c",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "a"},
+				{Type: token.Illegal, Literal: "&"},
+				{Type: token.Ident, Literal: "b"},
+				{Type: token.Illegal, Literal: "|"},
+				{Type: token.Ident, Literal: "c"},
+			},
+		},
+		{
+			name: "ParsesCompoundAssignmentOperators",
+			src:  "x += 1; x -= 1; x *= 2; x /= 2;",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.PlusAssign, Literal: "+="},
+				{Type: token.Int, Literal: "1"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.MinusAssign, Literal: "-="},
+				{Type: token.Int, Literal: "1"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.AsteriskAssign, Literal: "*="},
+				{Type: token.Int, Literal: "2"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.SlashAssign, Literal: "/="},
+				{Type: token.Int, Literal: "2"},
+				{Type: token.Semicolon, Literal: ";"},
+			},
+		},
+		{
+			name: "ParsesOperatorNotFollowedByAssign",
+			src:  "x + =y",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Plus, Literal: "+"},
+				{Type: token.Assign, Literal: "="},
+				{Type: token.Ident, Literal: "y"},
+			},
+		},
+		{
+			name: "ParsesSquareBrackets",
+			src:  "[1, 2, 3]",
+			want: []token.Token{
+				{Type: token.LBracket, Literal: "["},
+				{Type: token.Int, Literal: "1"},
+				{Type: token.Comma, Literal: ","},
+				{Type: token.Int, Literal: "2"},
+				{Type: token.Comma, Literal: ","},
+				{Type: token.Int, Literal: "3"},
+				{Type: token.RBracket, Literal: "]"},
+			},
+		},
+		{
+			name: "ParsesColon",
+			src:  `{"a": 1}`,
+			want: []token.Token{
+				{Type: token.LBrace, Literal: "{"},
+				{Type: token.String, Literal: "a"},
+				{Type: token.Colon, Literal: ":"},
+				{Type: token.Int, Literal: "1"},
+				{Type: token.RBrace, Literal: "}"},
+			},
+		},
+		{
+			name: "ParsesWhileKeyword",
+			src:  "while (x) {} whilex",
+			want: []token.Token{
+				{Type: token.While, Literal: "while"},
+				{Type: token.LParen, Literal: "("},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.RParen, Literal: ")"},
+				{Type: token.LBrace, Literal: "{"},
+				{Type: token.RBrace, Literal: "}"},
+				{Type: token.Ident, Literal: "whilex"},
+			},
+		},
+		{
+			name: "ParsesForKeyword",
+			src:  "for format forEach",
+			want: []token.Token{
+				{Type: token.For, Literal: "for"},
+				{Type: token.Ident, Literal: "format"},
+				{Type: token.Ident, Literal: "forEach"},
+			},
+		},
+		{
+			name: "ParsesBreakAndContinueKeywords",
+			src:  "break continue breakpoint continues",
+			want: []token.Token{
+				{Type: token.Break, Literal: "break"},
+				{Type: token.Continue, Literal: "continue"},
+				{Type: token.Ident, Literal: "breakpoint"},
+				{Type: token.Ident, Literal: "continues"},
+			},
+		},
+		{
+			name: "ParsesNullKeyword",
+			src:  "let x = null; nullish",
+			want: []token.Token{
+				{Type: token.Let, Literal: "let"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Assign, Literal: "="},
+				{Type: token.Null, Literal: "null"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "nullish"},
+			},
+		},
+		{
+			name: "ParsesMacroKeyword",
+			src:  "macro(x) { quote(unquote(x)) } macros",
+			want: []token.Token{
+				{Type: token.Macro, Literal: "macro"},
+				{Type: token.LParen, Literal: "("},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.RParen, Literal: ")"},
+				{Type: token.LBrace, Literal: "{"},
+				{Type: token.Ident, Literal: "quote"},
+				{Type: token.LParen, Literal: "("},
+				{Type: token.Ident, Literal: "unquote"},
+				{Type: token.LParen, Literal: "("},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.RParen, Literal: ")"},
+				{Type: token.RParen, Literal: ")"},
+				{Type: token.RBrace, Literal: "}"},
+				{Type: token.Ident, Literal: "macros"},
+			},
+		},
+		{
+			name: "ParsesDotBetweenIdentifiers",
+			src:  "a.b 3.14 x.5",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "a"},
+				{Type: token.Dot, Literal: "."},
+				{Type: token.Ident, Literal: "b"},
+				{Type: token.Float, Literal: "3.14"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Dot, Literal: "."},
+				{Type: token.Int, Literal: "5"},
+			},
+		},
 		{
 			name: "ParsesAllIntegers",
 			src:  "0 1 2 3 4 5 6 7 8 9",
@@ -132,6 +323,51 @@ if (5 < 10) {
 				{Type: token.Int, Literal: "9"},
 			},
 		},
+		{
+			name: "ParsesHexadecimalIntegers",
+			src:  "0x0 0xdeadBEEF 0X10",
+			want: []token.Token{
+				{Type: token.Int, Literal: "0x0"},
+				{Type: token.Int, Literal: "0xdeadBEEF"},
+				{Type: token.Int, Literal: "0X10"},
+			},
+		},
+		{
+			name: "StripsUnderscoresSeparatingDigits",
+			src:  "1_000_000 0xFF_FF 1_000.000_1",
+			want: []token.Token{
+				{Type: token.Int, Literal: "1000000"},
+				{Type: token.Int, Literal: "0xFFFF"},
+				{Type: token.Float, Literal: "1000.0001"},
+			},
+		},
+		{
+			name: "ParsesLeadingUnderscoreAsIdentifier",
+			src:  "_5",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "_5"},
+			},
+		},
+		{
+			name: "ParsesFloats",
+			src:  "0.0 10.25 3.14",
+			want: []token.Token{
+				{Type: token.Float, Literal: "0.0"},
+				{Type: token.Float, Literal: "10.25"},
+				{Type: token.Float, Literal: "3.14"},
+			},
+		},
+		{
+			name: "DoesNotParseDotAsDecimalPointIfNotFollowedByDigit",
+			src:  "5.foo 5.",
+			want: []token.Token{
+				{Type: token.Int, Literal: "5"},
+				{Type: token.Dot, Literal: "."},
+				{Type: token.Ident, Literal: "foo"},
+				{Type: token.Int, Literal: "5"},
+				{Type: token.Dot, Literal: "."},
+			},
+		},
 		{
 			name: "ReturnsIllegalTokenTypeForUnknownCharaceters",
 			src:  "\\+\\+",
@@ -142,6 +378,137 @@ if (5 < 10) {
 				{Type: token.Plus, Literal: "+"},
 			},
 		},
+		{
+			name: "ParsesEmptyString",
+			src:  `""`,
+			want: []token.Token{
+				{Type: token.String, Literal: ""},
+			},
+		},
+		{
+			name: "ParsesStringContainingSpaces",
+			src:  `"hello world" "  foo  "`,
+			want: []token.Token{
+				{Type: token.String, Literal: "hello world"},
+				{Type: token.String, Literal: "  foo  "},
+			},
+		},
+		{
+			name: "ParsesEscapeSequencesInStrings",
+			src:  `"a\nb" "a\tb" "a\rb" "a\"b" "a\\b"`,
+			want: []token.Token{
+				{Type: token.String, Literal: "a\nb"},
+				{Type: token.String, Literal: "a\tb"},
+				{Type: token.String, Literal: "a\rb"},
+				{Type: token.String, Literal: `a"b`},
+				{Type: token.String, Literal: `a\b`},
+			},
+		},
+		{
+			name: "ParsesRawStringsWithoutReplacingEscapeSequences",
+			src:  "`a\\nb` `C:\\foo\\\"bar\\\"`",
+			want: []token.Token{
+				{Type: token.String, Literal: `a\nb`},
+				{Type: token.String, Literal: `C:\foo\"bar\"`},
+			},
+		},
+		{
+			name: "ParsesRawStringsSpanningMultipleLines",
+			src:  "`foo\n  bar\n`",
+			want: []token.Token{
+				{Type: token.String, Literal: "foo\n  bar\n"},
+			},
+		},
+		{
+			name: "ParsesCharacters",
+			src:  `'a' ' ' 'é' '"'`,
+			want: []token.Token{
+				{Type: token.Char, Literal: "a"},
+				{Type: token.Char, Literal: " "},
+				{Type: token.Char, Literal: "é"},
+				{Type: token.Char, Literal: `"`},
+			},
+		},
+		{
+			name: "ParsesEscapeSequencesInCharacters",
+			src:  `'\n' '\t' '\r' '\'' '\\'`,
+			want: []token.Token{
+				{Type: token.Char, Literal: "\n"},
+				{Type: token.Char, Literal: "\t"},
+				{Type: token.Char, Literal: "\r"},
+				{Type: token.Char, Literal: "'"},
+				{Type: token.Char, Literal: `\`},
+			},
+		},
+		{
+			name: "IgnoresCommentAtEndOfLine",
+			src: `let x = 5; // x is 5
+x`,
+			want: []token.Token{
+				{Type: token.Let, Literal: "let"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Assign, Literal: "="},
+				{Type: token.Int, Literal: "5"},
+				{Type: token.Semicolon, Literal: ";"},
+				{Type: token.Ident, Literal: "x"},
+			},
+		},
+		{
+			name: "IgnoresCommentOnOwnLine",
+			src: `// the first line
+x;
+// the last line`,
+			want: []token.Token{
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Semicolon, Literal: ";"},
+			},
+		},
+		{
+			name: "IgnoresSingleLineBlockComment",
+			src:  `let /* the name */ x = /**/ 5;`,
+			want: []token.Token{
+				{Type: token.Let, Literal: "let"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Assign, Literal: "="},
+				{Type: token.Int, Literal: "5"},
+				{Type: token.Semicolon, Literal: ";"},
+			},
+		},
+		{
+			name: "IgnoresMultiLineBlockComment",
+			src: `/*
+ * x is 5
+ **/
+x`,
+			want: []token.Token{
+				{Type: token.Ident, Literal: "x"},
+			},
+		},
+		{
+			name: "DoesNotParseCommentInsideString",
+			src:  `"http://example.com"`,
+			want: []token.Token{
+				{Type: token.String, Literal: "http://example.com"},
+			},
+		},
+		{
+			name: "ParsesNonASCIIIdentifiers",
+			src:  "café π _αβγ1 x٣",
+			want: []token.Token{
+				{Type: token.Ident, Literal: "café"},
+				{Type: token.Ident, Literal: "π"},
+				{Type: token.Ident, Literal: "_αβγ1"},
+				{Type: token.Ident, Literal: "x٣"},
+			},
+		},
+		{
+			name: "ReturnsIllegalTokenTypeForNonLetterRunes",
+			src:  "€5",
+			want: []token.Token{
+				{Type: token.Illegal, Literal: "€"},
+				{Type: token.Int, Literal: "5"},
+			},
+		},
 		{
 			name: "ReturnsNoTokensIfSourceCodeIsEmpty",
 			src:  "",
@@ -158,10 +525,19 @@ if (5 < 10) {
 		t.Run(tc.name, func(t *testing.T) {
 			lexer := lexer.New(tc.src)
 			got := []token.Token{}
-			for nextToken := lexer.NextToken(); nextToken.Type != token.EOF; nextToken = lexer.NextToken() {
+			for {
+				nextToken, err := lexer.NextToken()
+				if err != nil {
+					t.Fatalf("NextToken() returned unexpected error from source %q: %s", tc.src, err)
+				}
+				if nextToken.Type == token.EOF {
+					break
+				}
 				got = append(got, nextToken)
 			}
-			if diff := cmp.Diff(tc.want, got); diff != "" {
+			// token positions are tested separately
+			ignorePositions := cmpopts.IgnoreFields(token.Token{}, "Line", "Column")
+			if diff := cmp.Diff(tc.want, got, ignorePositions); diff != "" {
 				t.Fatalf("NextToken() returned incorrect tokens from source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
 			}
 		})
@@ -177,7 +553,8 @@ func TestNextTokenOnlyAllowsIdentsToStartWithLettersAndUnderscores(t *testing.T)
 		}
 
 		src := string(rune(i)) + "a"
-		firstToken := lexer.New(src).NextToken()
+		// some characters (like an unterminated ") cause an error but we only care about the type of the token here
+		firstToken, _ := lexer.New(src).NextToken()
 
 		if ('A' <= i && i <= 'Z') || ('a' <= i && i <= 'z') || i == '_' {
 			t.Run(string(rune(i))+"IsValid", func(t *testing.T) {
@@ -204,11 +581,14 @@ func TestNextTokenOnlyAllowsIdentsToContainLettersNumbersAndUnderscoreAfterFirst
 		}
 
 		src := "a" + string(rune(i))
-		firstToken := lexer.New(src).NextToken()
+		firstToken, err := lexer.New(src).NextToken()
+		if err != nil {
+			t.Fatalf("NextToken() returned unexpected error from source %q: %s", src, err)
+		}
 
 		if ('A' <= i && i <= 'Z') || ('a' <= i && i <= 'z') || ('0' <= i && i <= '9') || i == '_' {
 			t.Run(string(rune(i))+"IsValid", func(t *testing.T) {
-				want := token.Token{Type: token.Ident, Literal: src}
+				want := token.Token{Type: token.Ident, Literal: src, Line: 1, Column: 1}
 				if firstToken != want {
 					t.Fatalf("NextToken() = %+v for source %q, want %+v", firstToken, src, want)
 				}
@@ -216,7 +596,7 @@ func TestNextTokenOnlyAllowsIdentsToContainLettersNumbersAndUnderscoreAfterFirst
 		} else {
 			t.Run(string(rune(i))+"IsNotValid", func(t *testing.T) {
 				// character isn't valid so first token should just be "a"
-				want := token.Token{Type: token.Ident, Literal: "a"}
+				want := token.Token{Type: token.Ident, Literal: "a", Line: 1, Column: 1}
 				if firstToken != want {
 					t.Fatalf("NextToken() = %+v for source %q, want %+v", firstToken, src, want)
 				}
@@ -227,9 +607,516 @@ func TestNextTokenOnlyAllowsIdentsToContainLettersNumbersAndUnderscoreAfterFirst
 
 func TestNextTokenReturnsEOFIfCalledAfterEOFReturned(t *testing.T) {
 	lexer := lexer.New("")
-	want := []token.Token{{Type: token.EOF}, {Type: token.EOF}}
-	got := []token.Token{lexer.NextToken(), lexer.NextToken()}
+	want := []token.Token{{Type: token.EOF, Line: 1, Column: 1}, {Type: token.EOF, Line: 1, Column: 1}}
+	got := []token.Token{}
+	for i := 0; i < 2; i++ {
+		nextToken, err := lexer.NextToken()
+		if err != nil {
+			t.Fatalf("NextToken() returned unexpected error on empty source: %s", err)
+		}
+		got = append(got, nextToken)
+	}
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Fatalf("NextToken() returned incorrect tokens when called twice on empty source\ndiff:\n--- want\n+++ got\n%s", diff)
 	}
 }
+
+func TestNextTokenReturnsTokenPositions(t *testing.T) {
+	src := `let x = 5;
+	let y = "foo
+bar";
+  x + y π y /* a block comment
+spanning lines */ z
+` + "`raw\nstring` z\n"
+	want := []token.Token{
+		{Type: token.Let, Literal: "let", Line: 1, Column: 1},
+		{Type: token.Ident, Literal: "x", Line: 1, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 1, Column: 7},
+		{Type: token.Int, Literal: "5", Line: 1, Column: 9},
+		{Type: token.Semicolon, Literal: ";", Line: 1, Column: 10},
+		{Type: token.Let, Literal: "let", Line: 2, Column: 2},
+		{Type: token.Ident, Literal: "y", Line: 2, Column: 6},
+		{Type: token.Assign, Literal: "=", Line: 2, Column: 8},
+		{Type: token.String, Literal: "foo\nbar", Line: 2, Column: 10},
+		{Type: token.Semicolon, Literal: ";", Line: 3, Column: 5},
+		{Type: token.Ident, Literal: "x", Line: 4, Column: 3},
+		{Type: token.Plus, Literal: "+", Line: 4, Column: 5},
+		{Type: token.Ident, Literal: "y", Line: 4, Column: 7},
+		{Type: token.Ident, Literal: "π", Line: 4, Column: 9},
+		{Type: token.Ident, Literal: "y", Line: 4, Column: 11},
+		{Type: token.Ident, Literal: "z", Line: 5, Column: 19},
+		{Type: token.String, Literal: "raw\nstring", Line: 6, Column: 1},
+		{Type: token.Ident, Literal: "z", Line: 7, Column: 9},
+		{Type: token.EOF, Literal: "", Line: 8, Column: 1},
+	}
+
+	l := lexer.New(src)
+	got := []token.Token{}
+	for {
+		nextToken, err := l.NextToken()
+		if err != nil {
+			t.Fatalf("NextToken() returned unexpected error from source %q: %s", src, err)
+		}
+		got = append(got, nextToken)
+		if nextToken.Type == token.EOF {
+			break
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("NextToken() returned incorrect tokens from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestNextTokenReturnsErrorForMalformedTokens(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want error
+	}{
+		{
+			name: "UnterminatedString",
+			src:  `let x = "foo`,
+			want: &lexer.UnterminatedStringError{Line: 1, Column: 9},
+		},
+		{
+			name: "UnterminatedRawString",
+			src:  "x;\n  `foo\nbar",
+			want: &lexer.UnterminatedStringError{Line: 2, Column: 3},
+		},
+		{
+			name: "UnterminatedStringEndingInBackslash",
+			src:  `"foo\`,
+			want: &lexer.UnterminatedStringError{Line: 1, Column: 1},
+		},
+		{
+			name: "HexadecimalPrefixWithoutDigits",
+			src:  "1 + 0x;",
+			want: &lexer.InvalidNumberError{Literal: "0x", Reason: "no digits after 0x prefix", Line: 1, Column: 5},
+		},
+		{
+			name: "TrailingUnderscoreInNumber",
+			src:  "let x = 5_;",
+			want: &lexer.InvalidNumberError{Literal: "5_", Reason: "trailing underscore", Line: 1, Column: 9},
+		},
+		{
+			name: "TrailingUnderscoreBeforeDecimalPoint",
+			src:  "1_.5",
+			want: &lexer.InvalidNumberError{Literal: "1_", Reason: "trailing underscore", Line: 1, Column: 1},
+		},
+		{
+			name: "ConsecutiveUnderscoresInNumber",
+			src:  "1__0",
+			want: &lexer.InvalidNumberError{Literal: "1__", Reason: "consecutive underscores", Line: 1, Column: 1},
+		},
+		{
+			name: "UnterminatedBlockComment",
+			src: `x;
+  /* foo
+bar`,
+			want: &lexer.UnterminatedCommentError{Line: 2, Column: 3},
+		},
+		{
+			name: "InvalidUTF8",
+			src:  "let x = \xff;",
+			want: &lexer.InvalidUTF8Error{Byte: 0xff, Line: 1, Column: 9},
+		},
+		{
+			name: "EmptyCharacter",
+			src:  "x = '';",
+			want: &lexer.InvalidCharError{Reason: "empty character literal", Line: 1, Column: 5},
+		},
+		{
+			name: "MultipleCharactersInCharacter",
+			src:  "x = 'ab';",
+			want: &lexer.InvalidCharError{Reason: "more than one character in character literal", Line: 1, Column: 5},
+		},
+		{
+			name: "UnterminatedCharacter",
+			src:  "'a",
+			want: &lexer.InvalidCharError{Reason: "unterminated character literal", Line: 1, Column: 1},
+		},
+		{
+			name: "InvalidEscapeSequenceInCharacter",
+			src:  `'\"'`,
+			want: &lexer.InvalidEscapeError{Char: '"', Line: 1, Column: 3},
+		},
+		{
+			name: "InvalidEscapeSequence",
+			src:  `"foo\qbar"`,
+			want: &lexer.InvalidEscapeError{Char: 'q', Line: 1, Column: 6},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := lexer.New(tc.src)
+			for {
+				nextToken, err := l.NextToken()
+				if err != nil {
+					if diff := cmp.Diff(tc.want, err); diff != "" {
+						t.Fatalf("NextToken() returned incorrect error from source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+					}
+					return
+				}
+				if nextToken.Type == token.EOF {
+					t.Fatalf("NextToken() returned no error from source %q, want %q", tc.src, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectErrorsRecordsErrorsAndContinuesLexing(t *testing.T) {
+	src := "let a = \xff;\nlet b = 1;\n\nlet c = \"\\q\" + \xfe;\nlet d = 1__0; c"
+	wantTokens := []token.Token{
+		{Type: token.Let, Literal: "let", Line: 1, Column: 1},
+		{Type: token.Ident, Literal: "a", Line: 1, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 1, Column: 7},
+		{Type: token.Illegal, Literal: "\xff", Line: 1, Column: 9},
+		{Type: token.Semicolon, Literal: ";", Line: 1, Column: 10},
+		{Type: token.Let, Literal: "let", Line: 2, Column: 1},
+		{Type: token.Ident, Literal: "b", Line: 2, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 2, Column: 7},
+		{Type: token.Int, Literal: "1", Line: 2, Column: 9},
+		{Type: token.Semicolon, Literal: ";", Line: 2, Column: 10},
+		{Type: token.Let, Literal: "let", Line: 4, Column: 1},
+		{Type: token.Ident, Literal: "c", Line: 4, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 4, Column: 7},
+		{Type: token.Illegal, Literal: `"\q"`, Line: 4, Column: 9},
+		{Type: token.Plus, Literal: "+", Line: 4, Column: 14},
+		{Type: token.Illegal, Literal: "\xfe", Line: 4, Column: 16},
+		{Type: token.Semicolon, Literal: ";", Line: 4, Column: 17},
+		{Type: token.Let, Literal: "let", Line: 5, Column: 1},
+		{Type: token.Ident, Literal: "d", Line: 5, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 5, Column: 7},
+		{Type: token.Illegal, Literal: "1__0", Line: 5, Column: 9},
+		{Type: token.Semicolon, Literal: ";", Line: 5, Column: 13},
+		{Type: token.Ident, Literal: "c", Line: 5, Column: 15},
+		{Type: token.EOF, Literal: "", Line: 5, Column: 16},
+	}
+	wantErrs := []error{
+		&lexer.InvalidUTF8Error{Byte: 0xff, Line: 1, Column: 9},
+		&lexer.InvalidEscapeError{Char: 'q', Line: 4, Column: 11},
+		&lexer.InvalidUTF8Error{Byte: 0xfe, Line: 4, Column: 16},
+		&lexer.InvalidNumberError{Literal: "1__", Reason: "consecutive underscores", Line: 5, Column: 9},
+	}
+
+	l := lexer.New(src)
+	l.CollectErrors()
+	gotTokens, err := readAllTokens(l)
+	if err != nil {
+		t.Fatalf("NextToken() returned unexpected error from source %q after CollectErrors(): %s", src, err)
+	}
+	if diff := cmp.Diff(wantTokens, gotTokens); diff != "" {
+		t.Errorf("NextToken() returned incorrect tokens from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+	if diff := cmp.Diff(wantErrs, l.Errors()); diff != "" {
+		t.Errorf("Errors() returned incorrect errors from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestCollectErrorsReturnsUnterminatedLiteralsAsIllegalTokens(t *testing.T) {
+	src := "x /* foo"
+	wantTokens := []token.Token{
+		{Type: token.Ident, Literal: "x", Line: 1, Column: 1},
+		{Type: token.Illegal, Literal: "/* foo", Line: 1, Column: 3},
+		{Type: token.EOF, Literal: "", Line: 1, Column: 9},
+	}
+	wantErrs := []error{&lexer.UnterminatedCommentError{Line: 1, Column: 3}}
+
+	l := lexer.New(src)
+	l.CollectErrors()
+	gotTokens, err := readAllTokens(l)
+	if err != nil {
+		t.Fatalf("NextToken() returned unexpected error from source %q after CollectErrors(): %s", src, err)
+	}
+	if diff := cmp.Diff(wantTokens, gotTokens); diff != "" {
+		t.Errorf("NextToken() returned incorrect tokens from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+	if diff := cmp.Diff(wantErrs, l.Errors()); diff != "" {
+		t.Errorf("Errors() returned incorrect errors from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestEmitCommentsReturnsCommentsAsTokens(t *testing.T) {
+	src := "// doc\nlet x = 1; // trailing\n/* block\ncomment */ x/**/\n//"
+	want := []token.Token{
+		{Type: token.Comment, Literal: "// doc", Line: 1, Column: 1},
+		{Type: token.Let, Literal: "let", Line: 2, Column: 1},
+		{Type: token.Ident, Literal: "x", Line: 2, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 2, Column: 7},
+		{Type: token.Int, Literal: "1", Line: 2, Column: 9},
+		{Type: token.Semicolon, Literal: ";", Line: 2, Column: 10},
+		{Type: token.Comment, Literal: "// trailing", Line: 2, Column: 12},
+		{Type: token.Comment, Literal: "/* block\ncomment */", Line: 3, Column: 1},
+		{Type: token.Ident, Literal: "x", Line: 4, Column: 12},
+		{Type: token.Comment, Literal: "/**/", Line: 4, Column: 13},
+		{Type: token.Comment, Literal: "//", Line: 5, Column: 1},
+		{Type: token.EOF, Literal: "", Line: 5, Column: 3},
+	}
+
+	for name, l := range map[string]*lexer.Lexer{
+		"New":       lexer.New(src),
+		"NewReader": lexer.NewReader(iotest.OneByteReader(strings.NewReader(src))),
+	} {
+		t.Run(name, func(t *testing.T) {
+			l.EmitComments()
+			got, err := readAllTokens(l)
+			if err != nil {
+				t.Fatalf("NextToken() returned unexpected error from source %q after EmitComments(): %s", src, err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("NextToken() returned incorrect tokens from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+			}
+		})
+	}
+}
+
+func TestEmitCommentsReturnsErrorForUnterminatedComment(t *testing.T) {
+	src := "x /* foo"
+	want := &lexer.UnterminatedCommentError{Line: 1, Column: 3}
+
+	l := lexer.New(src)
+	l.EmitComments()
+	_, err := readAllTokens(l)
+
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Fatalf("NextToken() returned incorrect error from source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestPeekTokenReturnsSameTokenAsNextToken(t *testing.T) {
+	src := "let x = 5;"
+	l := lexer.New(src)
+	for {
+		peekedToken, peekedErr := l.PeekToken()
+		// peeking again shouldn't advance the lexer
+		peekedAgainToken, peekedAgainErr := l.PeekToken()
+		nextToken, nextErr := l.NextToken()
+		if peekedErr != nil || peekedAgainErr != nil || nextErr != nil {
+			t.Fatalf("unexpected error from source %q: PeekToken() returned %v then %v, NextToken() returned %v", src, peekedErr, peekedAgainErr, nextErr)
+		}
+		if diff := cmp.Diff(nextToken, peekedToken); diff != "" {
+			t.Fatalf("PeekToken() returned different token to NextToken() from source %q\ndiff:\n--- NextToken\n+++ PeekToken\n%s", src, diff)
+		}
+		if diff := cmp.Diff(nextToken, peekedAgainToken); diff != "" {
+			t.Fatalf("second PeekToken() returned different token to NextToken() from source %q\ndiff:\n--- NextToken\n+++ PeekToken\n%s", src, diff)
+		}
+		if nextToken.Type == token.EOF {
+			break
+		}
+	}
+}
+
+func TestPeekTokenReturnsEOFRepeatedlyAtEndOfSource(t *testing.T) {
+	l := lexer.New("x")
+	if _, err := l.NextToken(); err != nil {
+		t.Fatalf("NextToken() returned unexpected error: %s", err)
+	}
+	want := token.Token{Type: token.EOF, Line: 1, Column: 2}
+	for i := 0; i < 3; i++ {
+		got, err := l.PeekToken()
+		if err != nil {
+			t.Fatalf("PeekToken() returned unexpected error on call %d: %s", i+1, err)
+		}
+		if got != want {
+			t.Fatalf("PeekToken() = %+v on call %d, want %+v", got, i+1, want)
+		}
+	}
+	got, err := l.NextToken()
+	if err != nil {
+		t.Fatalf("NextToken() returned unexpected error after peeking: %s", err)
+	}
+	if got != want {
+		t.Fatalf("NextToken() = %+v after peeking, want %+v", got, want)
+	}
+}
+
+func TestPeekTokenReturnsErrorForMalformedToken(t *testing.T) {
+	l := lexer.New(`"foo`)
+	want := &lexer.UnterminatedStringError{Line: 1, Column: 1}
+	_, peekedErr := l.PeekToken()
+	if diff := cmp.Diff(want, peekedErr); diff != "" {
+		t.Fatalf("PeekToken() returned incorrect error\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+	_, nextErr := l.NextToken()
+	if diff := cmp.Diff(want, nextErr); diff != "" {
+		t.Fatalf("NextToken() after PeekToken() returned incorrect error\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestResetReinitialisesLexerWithNewSource(t *testing.T) {
+	l := lexer.New(`let x = 5;
+"unterminated`)
+	// leave the lexer with an error, a peeked token, and a position which isn't at the start of the source
+	readAllTokens(l)
+	l.PeekToken()
+
+	src := `let y =
+  10;`
+	l.Reset(src)
+	want := []token.Token{
+		{Type: token.Let, Literal: "let", Line: 1, Column: 1},
+		{Type: token.Ident, Literal: "y", Line: 1, Column: 5},
+		{Type: token.Assign, Literal: "=", Line: 1, Column: 7},
+		{Type: token.Int, Literal: "10", Line: 2, Column: 3},
+		{Type: token.Semicolon, Literal: ";", Line: 2, Column: 5},
+		{Type: token.EOF, Literal: "", Line: 2, Column: 6},
+	}
+	got, err := readAllTokens(l)
+	if err != nil {
+		t.Fatalf("NextToken() returned unexpected error after Reset(%q): %s", src, err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("NextToken() returned incorrect tokens after Reset(%q)\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestTokensSendsSameTokensAsNextToken(t *testing.T) {
+	src := `let add = fn(x, y) { x + y; };
+let result = add(5, 0x10) >= "foo\q";`
+	l := lexer.New(src)
+	l.CollectErrors()
+	wantTokens, err := readAllTokens(l)
+	if err != nil {
+		t.Fatalf("NextToken() returned unexpected error from source %q after CollectErrors(): %s", src, err)
+	}
+
+	l = lexer.New(src)
+	gotTokens := []token.Token{}
+	for tok := range l.Tokens(context.Background()) {
+		gotTokens = append(gotTokens, tok)
+	}
+	if diff := cmp.Diff(wantTokens, gotTokens); diff != "" {
+		t.Errorf("Tokens() sent different tokens to those returned by NextToken()\ndiff:\n--- NextToken\n+++ Tokens\n%s", diff)
+	}
+	wantErrs := []error{&lexer.InvalidEscapeError{Char: 'q', Line: 2, Column: 35}}
+	if diff := cmp.Diff(wantErrs, l.Errors()); diff != "" {
+		t.Errorf("Errors() returned incorrect errors after Tokens() channel closed\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestTokensSendsReadErrorAsIllegalToken(t *testing.T) {
+	readErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("x"), iotest.ErrReader(readErr))
+	var lastToken token.Token
+	for tok := range lexer.NewReader(r).Tokens(context.Background()) {
+		lastToken = tok
+	}
+	if lastToken.Type != token.Illegal || lastToken.Literal != readErr.Error() {
+		t.Fatalf("Tokens() sent %v as last token, want ILLEGAL(%q)", lastToken, readErr)
+	}
+}
+
+func TestTokensStopsWhenContextCancelled(t *testing.T) {
+	src := strings.Repeat("x ", 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tokens := lexer.New(src).Tokens(ctx)
+	<-tokens
+	<-tokens
+	cancel()
+
+	received := 2
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-tokens:
+			if !ok {
+				if received > 3 {
+					t.Fatalf("Tokens() sent %d tokens, want at most 3 after cancelling context after receiving 2", received)
+				}
+				return
+			}
+			received++
+		case <-timeout:
+			t.Fatalf("Tokens() channel wasn't closed after context was cancelled")
+		}
+	}
+}
+
+func TestNewReaderReturnsSameTokensAsNew(t *testing.T) {
+	srcs := map[string]string{
+		"Statements": `let add = fn(x, y) {
+	x + y; // add them
+};
+/* call
+   add */
+let café = add(0x10, 1_000.5);
+if (café >= 10 && "a\tb" != "") { return [1, 2]; }
+`,
+		// long enough to span many reads so that tokens fall across the boundaries between reads
+		"LongSource": strings.Repeat("let foobar = \"héllo\" + 12345; /* comment */\n", 1000),
+		"Error":      `let x = "foo\qbar";`,
+	}
+	readers := map[string]func(src string) io.Reader{
+		"FullReader": func(src string) io.Reader {
+			return strings.NewReader(src)
+		},
+		"OneByteReader": func(src string) io.Reader {
+			return iotest.OneByteReader(strings.NewReader(src))
+		},
+		"HalfReader": func(src string) io.Reader {
+			return iotest.HalfReader(strings.NewReader(src))
+		},
+	}
+
+	for srcName, src := range srcs {
+		for readerName, newReader := range readers {
+			t.Run(srcName+readerName, func(t *testing.T) {
+				wantTokens, wantErr := readAllTokens(lexer.New(src))
+				gotTokens, gotErr := readAllTokens(lexer.NewReader(newReader(src)))
+				if diff := cmp.Diff(wantTokens, gotTokens); diff != "" {
+					t.Errorf("NewReader(src).NextToken() returned different tokens to New(src).NextToken()\ndiff:\n--- New\n+++ NewReader\n%s", diff)
+				}
+				if diff := cmp.Diff(wantErr, gotErr); diff != "" {
+					t.Errorf("NewReader(src).NextToken() returned different error to New(src).NextToken()\ndiff:\n--- New\n+++ NewReader\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestNextTokenReturnsReadErrorFromReader(t *testing.T) {
+	readErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("let x = 5"), iotest.ErrReader(readErr))
+	_, err := readAllTokens(lexer.NewReader(r))
+	if err != readErr {
+		t.Fatalf("NextToken() returned error %v, want %v", err, readErr)
+	}
+}
+
+// readAllTokens returns all of the tokens from the given Lexer up to and including the EOF token, or up to the first
+// error.
+func readAllTokens(l *lexer.Lexer) ([]token.Token, error) {
+	tokens := []token.Token{}
+	for {
+		nextToken, err := l.NextToken()
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, nextToken)
+		if nextToken.Type == token.EOF {
+			return tokens, nil
+		}
+	}
+}
+
+func BenchmarkNextToken(b *testing.B) {
+	src := strings.Repeat("let identifier_1 = another_identifier + 1234567890 * 3.14159 - 0xdeadBEEF;\n", 1000)
+	l := lexer.New(src)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Reset(src)
+		for {
+			nextToken, err := l.NextToken()
+			if err != nil {
+				b.Fatalf("NextToken() returned unexpected error: %s", err)
+			}
+			if nextToken.Type == token.EOF {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package lexer
+
+import "github.com/marcuscaisey/monkey/token"
+
+// Scanner provides a convenient interface for reading the tokens from a [Lexer], similar to [bufio.Scanner]. Successive
+// calls to [Scanner.Scan] step through the tokens, stopping at the end of the source or at the first malformed token.
+type Scanner struct {
+	lexer *Lexer
+	tok   token.Token
+	err   error
+	done  bool
+}
+
+// NewScanner returns a new Scanner to read the tokens from the given Lexer.
+func NewScanner(l *Lexer) *Scanner {
+	return &Scanner{
+		lexer: l,
+	}
+}
+
+// Scan advances the Scanner to the next token, which will then be available through the [Scanner.Token] method. It
+// returns false when the end of the source is reached or a malformed token is encountered. After Scan returns false,
+// the [Scanner.Err] method will return the error that stopped scanning, or nil if the end of the source was reached.
+func (s *Scanner) Scan() bool {
+	if s.done {
+		return false
+	}
+	tok, err := s.lexer.NextToken()
+	if err != nil {
+		s.err = err
+		s.done = true
+		return false
+	}
+	if tok.Type == token.EOF {
+		s.done = true
+		return false
+	}
+	s.tok = tok
+	return true
+}
+
+// Token returns the most recent token read by a call to [Scanner.Scan].
+func (s *Scanner) Token() token.Token {
+	return s.tok
+}
+
+// Err returns the first error that was encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
@@ -0,0 +1,73 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/token"
+)
+
+func TestScanner(t *testing.T) {
+	testCases := []struct {
+		name    string
+		src     string
+		want    []token.Token
+		wantErr error
+	}{
+		{
+			name: "ReturnsAllTokensUntilEOF",
+			src:  "let x = 5;",
+			want: []token.Token{
+				{Type: token.Let, Literal: "let"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Assign, Literal: "="},
+				{Type: token.Int, Literal: "5"},
+				{Type: token.Semicolon, Literal: ";"},
+			},
+		},
+		{
+			name: "ReturnsNoTokensIfSourceCodeIsEmpty",
+			src:  "",
+			want: []token.Token{},
+		},
+		{
+			name: "StopsAtFirstError",
+			src:  `let x = "foo`,
+			want: []token.Token{
+				{Type: token.Let, Literal: "let"},
+				{Type: token.Ident, Literal: "x"},
+				{Type: token.Assign, Literal: "="},
+			},
+			wantErr: &lexer.UnterminatedStringError{Line: 1, Column: 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := lexer.NewScanner(lexer.New(tc.src))
+			got := []token.Token{}
+			for scanner.Scan() {
+				got = append(got, scanner.Token())
+			}
+			ignorePositions := cmpopts.IgnoreFields(token.Token{}, "Line", "Column")
+			if diff := cmp.Diff(tc.want, got, ignorePositions); diff != "" {
+				t.Errorf("Scanner returned incorrect tokens from source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+			if diff := cmp.Diff(tc.wantErr, scanner.Err()); diff != "" {
+				t.Errorf("Err() returned incorrect error from source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestScanReturnsFalseAfterScanningStops(t *testing.T) {
+	scanner := lexer.NewScanner(lexer.New(`"foo`))
+	for i := 0; i < 2; i++ {
+		if scanner.Scan() {
+			t.Fatalf("Scan() = true on call %d after scanning stopped, want false", i+1)
+		}
+	}
+}
@@ -1,18 +1,121 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
 
+	"github.com/marcuscaisey/monkey/evaluator"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
 	"github.com/marcuscaisey/monkey/repl"
+	"github.com/marcuscaisey/monkey/token"
 )
 
+const usage = `usage:
+  monkey                        start the REPL
+  monkey [run] <file>           evaluate the Monkey source code in a file
+  monkey tokens [--json] <file> print the tokens in a file, as a JSON array if --json is given
+`
+
 func main() {
-	user, err := user.Current()
+	args := os.Args[1:]
+	if len(args) == 0 {
+		user, err := user.Current()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("Hello %v. Welcome to the Monkey REPL!\n", user.Username)
+		repl.Start(os.Stdin, os.Stdout)
+		return
+	}
+
+	cmd := "run"
+	if args[0] == "run" || args[0] == "tokens" {
+		cmd = args[0]
+		args = args[1:]
+	}
+	asJSON := false
+	if cmd == "tokens" && len(args) > 0 && args[0] == "--json" {
+		asJSON = true
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	name := args[0]
+	f, err := os.Open(name)
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+	var status int
+	if cmd == "tokens" {
+		status = printTokens(name, f, asJSON, os.Stdout, os.Stderr)
+	} else {
+		status = run(name, f, os.Stdin, os.Stdout, os.Stderr)
+	}
+	f.Close()
+	os.Exit(status)
+}
+
+// run evaluates the Monkey source code read from src, which was read from the file with the given name. Input to the
+// program is read from stdin, output from the program is written to stdout, and any errors are written to stderr. It
+// returns the exit status of the program, which is non-zero if the source couldn't be parsed or evaluating it returned
+// an error.
+func run(name string, src, stdin io.Reader, stdout, stderr io.Writer) int {
+	p := parser.New(lexer.NewReader(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		for _, err := range errs {
+			// errors with a position are printed like main.mk:1:5: msg and errors without one like main.mk: msg
+			if err.Line == 0 {
+				fmt.Fprintf(stderr, "%s: %s\n", name, err)
+			} else {
+				fmt.Fprintf(stderr, "%s:%s\n", name, err)
+			}
+		}
+		return 1
+	}
+	result := evaluator.New(stdout, stdin).Eval(program, object.NewEnvironment())
+	if err, ok := result.(*object.Error); ok {
+		fmt.Fprintf(stderr, "%s: %s\n", name, err.Inspect())
+		return 1
+	}
+	return 0
+}
+
+// printTokens prints the tokens in the Monkey source code read from src, which was read from the file with the given
+// name. The tokens are written to stdout one per line, or as a JSON array of objects with the keys type, literal, line,
+// and column if asJSON is true. The EOF token isn't included. If a malformed token is encountered, then the tokens
+// before it are printed, the error is written to stderr, and a non-zero exit status is returned.
+func printTokens(name string, src io.Reader, asJSON bool, stdout, stderr io.Writer) int {
+	tokens := []token.Token{}
+	scanner := lexer.NewScanner(lexer.NewReader(src))
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Token())
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(tokens); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return 1
+		}
+	} else {
+		for _, tok := range tokens {
+			fmt.Fprintln(stdout, tok)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "%s:%s\n", name, err)
+		return 1
 	}
-	fmt.Printf("Hello %v. Welcome to the Monkey REPL!\n", user.Username)
-	repl.Start(os.Stdin, os.Stdout)
+	return 0
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name       string
+		src        string
+		srcReader  io.Reader
+		stdin      string
+		wantStatus int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name: "Success",
+			src: `let fib = fn(n) {
+  if (n < 2) {
+    return n;
+  }
+  fib(n - 1) + fib(n - 2)
+};
+puts(fib(10));
+`,
+			wantStatus: 0,
+			wantStdout: "55\n",
+		},
+		{
+			name:       "ParseError",
+			src:        "puts(1);\nlet = 5;\n",
+			wantStatus: 1,
+			wantStderr: "main.mk:2:5: expected IDENT, got ASSIGN(\"=\")\n",
+		},
+		{
+			name:       "ReadError",
+			srcReader:  iotest.ErrReader(errors.New("disk on fire")),
+			wantStatus: 1,
+			wantStderr: "main.mk: disk on fire\n",
+		},
+		{
+			name:       "EvaluationError",
+			src:        "puts(1);\n1 + true;\nputs(2);\n",
+			wantStatus: 1,
+			wantStdout: "1\n",
+			wantStderr: "main.mk: error: type mismatch: INTEGER + BOOLEAN\n",
+		},
+		{
+			name:       "Input",
+			src:        "let name = gets();\nputs(\"Hello \" + name);\n",
+			stdin:      "Monkey\n",
+			wantStatus: 0,
+			wantStdout: "Hello Monkey\n",
+		},
+		{
+			name:       "Empty",
+			src:        "",
+			wantStatus: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			src := tc.srcReader
+			if src == nil {
+				src = strings.NewReader(tc.src)
+			}
+
+			status := run("main.mk", src, strings.NewReader(tc.stdin), stdout, stderr)
+
+			if status != tc.wantStatus {
+				t.Errorf("run() = %d, want %d", status, tc.wantStatus)
+			}
+			if diff := cmp.Diff(tc.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("run() wrote incorrect stdout\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("run() wrote incorrect stderr\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrintTokens(t *testing.T) {
+	testCases := []struct {
+		name       string
+		src        string
+		asJSON     bool
+		wantStatus int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "Text",
+			src:        "let x = 5;",
+			wantStatus: 0,
+			wantStdout: `LET("let")
+IDENT("x")
+ASSIGN("=")
+INT("5")
+SEMICOLON(";")
+`,
+		},
+		{
+			name:       "JSON",
+			src:        "let x =\n  \"hi\";",
+			asJSON:     true,
+			wantStatus: 0,
+			wantStdout: `[
+  {
+    "type": "LET",
+    "literal": "let",
+    "line": 1,
+    "column": 1
+  },
+  {
+    "type": "IDENT",
+    "literal": "x",
+    "line": 1,
+    "column": 5
+  },
+  {
+    "type": "ASSIGN",
+    "literal": "=",
+    "line": 1,
+    "column": 7
+  },
+  {
+    "type": "STRING",
+    "literal": "hi",
+    "line": 2,
+    "column": 3
+  },
+  {
+    "type": "SEMICOLON",
+    "literal": ";",
+    "line": 2,
+    "column": 7
+  }
+]
+`,
+		},
+		{
+			name:       "EmptyJSON",
+			src:        "",
+			asJSON:     true,
+			wantStatus: 0,
+			wantStdout: "[]\n",
+		},
+		{
+			name:       "MalformedToken",
+			src:        "x 2__0",
+			asJSON:     true,
+			wantStatus: 1,
+			wantStdout: `[
+  {
+    "type": "IDENT",
+    "literal": "x",
+    "line": 1,
+    "column": 1
+  }
+]
+`,
+			wantStderr: "main.mk:1:3: invalid number literal \"2__\": consecutive underscores\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			status := printTokens("main.mk", strings.NewReader(tc.src), tc.asJSON, stdout, stderr)
+
+			if status != tc.wantStatus {
+				t.Errorf("printTokens() = %d, want %d", status, tc.wantStatus)
+			}
+			if diff := cmp.Diff(tc.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("printTokens() wrote incorrect stdout\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("printTokens() wrote incorrect stderr\ndiff:\n--- want\n+++ got\n%s", diff)
+			}
+		})
+	}
+}
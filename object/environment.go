@@ -0,0 +1,63 @@
+package object
+
+import "sort"
+
+// Environment stores the values which are bound to identifiers. An Environment can be enclosed by an outer Environment,
+// in which case names which aren't bound in the Environment are looked up in the outer one.
+type Environment struct {
+	store map[string]Object
+	outer *Environment
+}
+
+// NewEnvironment returns an empty Environment.
+func NewEnvironment() *Environment {
+	return &Environment{store: map[string]Object{}}
+}
+
+// NewEnclosedEnvironment returns an empty Environment which is enclosed by the given outer Environment.
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	env := NewEnvironment()
+	env.outer = outer
+	return env
+}
+
+// Get returns the value bound to the given name and whether the name is bound. If the name isn't bound in this
+// Environment, then it's looked up in the enclosing Environments.
+func (e *Environment) Get(name string) (Object, bool) {
+	obj, ok := e.store[name]
+	if !ok && e.outer != nil {
+		return e.outer.Get(name)
+	}
+	return obj, ok
+}
+
+// Set binds the given value to the given name in this Environment, replacing any existing value. The enclosing
+// Environments are never modified, so binding a name which is bound in an enclosing Environment shadows it. The value
+// is returned.
+func (e *Environment) Set(name string, val Object) Object {
+	e.store[name] = val
+	return val
+}
+
+// Assign replaces the value bound to the given name in the innermost Environment where it's bound, which may be one
+// of the enclosing Environments. It returns false without binding the value if the name isn't bound anywhere.
+func (e *Environment) Assign(name string, val Object) bool {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = val
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the sorted names which are bound in this Environment. Names which are only bound in the enclosing
+// Environments aren't included.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,180 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/object"
+)
+
+func TestEnvironmentGetReturnsSetValue(t *testing.T) {
+	env := object.NewEnvironment()
+	want := &object.Integer{Value: 5}
+	if got := env.Set("x", want); got != want {
+		t.Fatalf("Set() returned %v, want the value that was set", got)
+	}
+
+	got, ok := env.Get("x")
+
+	if !ok {
+		t.Fatalf("Get(%q) returned ok = false, want true", "x")
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+}
+
+func TestEnvironmentSetReplacesValue(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("x", &object.Integer{Value: 5})
+	want := &object.Boolean{Value: true}
+	env.Set("x", want)
+
+	got, _ := env.Get("x")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+}
+
+func TestEnvironmentGetReturnsFalseForUnsetName(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("x", &object.Integer{Value: 5})
+
+	got, ok := env.Get("y")
+
+	if ok {
+		t.Fatalf("Get(%q) returned %v, true, want ok = false", "y", got)
+	}
+}
+
+func TestEnclosedEnvironmentGetReturnsOuterValue(t *testing.T) {
+	outer := object.NewEnvironment()
+	want := &object.Integer{Value: 5}
+	outer.Set("x", want)
+	inner := object.NewEnclosedEnvironment(outer)
+
+	got, ok := inner.Get("x")
+
+	if !ok {
+		t.Fatalf("Get(%q) returned ok = false, want true", "x")
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+}
+
+func TestEnclosedEnvironmentSetShadowsOuterValue(t *testing.T) {
+	outer := object.NewEnvironment()
+	wantOuter := &object.Integer{Value: 5}
+	outer.Set("x", wantOuter)
+	inner := object.NewEnclosedEnvironment(outer)
+	wantInner := &object.Integer{Value: 10}
+	inner.Set("x", wantInner)
+
+	gotInner, _ := inner.Get("x")
+	gotOuter, _ := outer.Get("x")
+
+	if diff := cmp.Diff(wantInner, gotInner); diff != "" {
+		t.Errorf("inner Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+	if diff := cmp.Diff(wantOuter, gotOuter); diff != "" {
+		t.Errorf("outer Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+}
+
+func TestEnclosedEnvironmentSetDoesNotBindInOuter(t *testing.T) {
+	outer := object.NewEnvironment()
+	inner := object.NewEnclosedEnvironment(outer)
+	inner.Set("x", &object.Integer{Value: 5})
+
+	got, ok := outer.Get("x")
+
+	if ok {
+		t.Fatalf("outer Get(%q) returned %v, true, want ok = false", "x", got)
+	}
+}
+
+func TestEnclosedEnvironmentGetChainsThroughEnvironments(t *testing.T) {
+	global := object.NewEnvironment()
+	wantX := &object.Integer{Value: 1}
+	global.Set("x", wantX)
+	middle := object.NewEnclosedEnvironment(global)
+	wantY := &object.Integer{Value: 2}
+	middle.Set("y", wantY)
+	inner := object.NewEnclosedEnvironment(middle)
+
+	gotX, okX := inner.Get("x")
+	gotY, okY := inner.Get("y")
+	gotZ, okZ := inner.Get("z")
+
+	if !okX {
+		t.Errorf("Get(%q) returned ok = false, want true", "x")
+	} else if diff := cmp.Diff(wantX, gotX); diff != "" {
+		t.Errorf("Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+	if !okY {
+		t.Errorf("Get(%q) returned ok = false, want true", "y")
+	} else if diff := cmp.Diff(wantY, gotY); diff != "" {
+		t.Errorf("Get(%q) returned incorrect value\ndiff:\n--- want\n+++ got\n%s", "y", diff)
+	}
+	if okZ {
+		t.Errorf("Get(%q) returned %v, true, want ok = false", "z", gotZ)
+	}
+}
+
+func TestEnvironmentNames(t *testing.T) {
+	outer := object.NewEnvironment()
+	outer.Set("outer", &object.Integer{Value: 1})
+	env := object.NewEnclosedEnvironment(outer)
+	env.Set("b", &object.Integer{Value: 2})
+	env.Set("c", &object.Integer{Value: 3})
+	env.Set("a", &object.Integer{Value: 4})
+	env.Set("b", &object.Integer{Value: 5})
+	want := []string{"a", "b", "c"}
+
+	got := env.Names()
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Names() returned incorrect names\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestEnvironmentNamesEmpty(t *testing.T) {
+	got := object.NewEnvironment().Names()
+
+	if len(got) != 0 {
+		t.Fatalf("Names() = %q, want no names", got)
+	}
+}
+
+func TestEnvironmentAssign(t *testing.T) {
+	outer := object.NewEnvironment()
+	outer.Set("x", &object.Integer{Value: 1})
+	inner := object.NewEnclosedEnvironment(outer)
+	want := &object.Integer{Value: 2}
+
+	if ok := inner.Assign("x", want); !ok {
+		t.Fatalf("Assign(%q) = false, want true", "x")
+	}
+
+	got, _ := outer.Get("x")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("outer Get(%q) returned incorrect value after inner Assign()\ndiff:\n--- want\n+++ got\n%s", "x", diff)
+	}
+	if names := inner.Names(); len(names) != 0 {
+		t.Fatalf("inner Names() = %v after Assign(), want no names", names)
+	}
+}
+
+func TestEnvironmentAssignReturnsFalseForUnsetName(t *testing.T) {
+	env := object.NewEnclosedEnvironment(object.NewEnvironment())
+
+	if ok := env.Assign("x", &object.Integer{Value: 1}); ok {
+		t.Fatalf("Assign(%q) = true, want false", "x")
+	}
+	if got, ok := env.Get("x"); ok {
+		t.Fatalf("Get(%q) returned %v, true after failed Assign(), want ok = false", "x", got)
+	}
+}
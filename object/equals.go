@@ -0,0 +1,77 @@
+package object
+
+// Equaler is the interface implemented by objects which are compared by value rather than by identity.
+type Equaler interface {
+	Object
+	// Equals returns whether the object is equal to other. Objects of different types are never equal.
+	Equals(other Object) bool
+}
+
+// Equals returns whether two objects are equal. Objects which implement [Equaler] are compared with their Equals method.
+// Other objects, like functions, are only equal to themselves.
+func Equals(a, b Object) bool {
+	if a, ok := a.(Equaler); ok {
+		return a.Equals(b)
+	}
+	return a == b
+}
+
+// Equals returns whether other is an [Integer] with the same value.
+func (i *Integer) Equals(other Object) bool {
+	o, ok := other.(*Integer)
+	return ok && i.Value == o.Value
+}
+
+// Equals returns whether other is a [BigInteger] with the same value.
+func (bi *BigInteger) Equals(other Object) bool {
+	o, ok := other.(*BigInteger)
+	return ok && bi.Value.Cmp(o.Value) == 0
+}
+
+// Equals returns whether other is a [String] with the same value.
+func (s *String) Equals(other Object) bool {
+	o, ok := other.(*String)
+	return ok && s.Value == o.Value
+}
+
+// Equals returns whether other is a [Boolean] with the same value.
+func (b *Boolean) Equals(other Object) bool {
+	o, ok := other.(*Boolean)
+	return ok && b.Value == o.Value
+}
+
+// Equals returns whether other is a [Null], since all nulls are equal.
+func (n *Null) Equals(other Object) bool {
+	_, ok := other.(*Null)
+	return ok
+}
+
+// Equals returns whether other is an [Array] with equal elements in the same order.
+func (a *Array) Equals(other Object) bool {
+	o, ok := other.(*Array)
+	if !ok || len(a.Elements) != len(o.Elements) {
+		return false
+	}
+	for i, element := range a.Elements {
+		if !Equals(element, o.Elements[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns whether other is a [Hash] with the same keys and equal values, regardless of the order that the pairs
+// were inserted in.
+func (h *Hash) Equals(other Object) bool {
+	o, ok := other.(*Hash)
+	if !ok || len(h.Pairs) != len(o.Pairs) {
+		return false
+	}
+	for key, pair := range h.Pairs {
+		otherPair, ok := o.Pairs[key]
+		if !ok || !Equals(pair.Value, otherPair.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/object"
+)
+
+func TestEquals(t *testing.T) {
+	function := newFunction()
+	builtin := &object.Builtin{}
+
+	testCases := []struct {
+		name string
+		a    object.Object
+		b    object.Object
+		want bool
+	}{
+		{name: "EqualIntegers", a: integer(1), b: integer(1), want: true},
+		{name: "UnequalIntegers", a: integer(1), b: integer(2), want: false},
+		{
+			name: "EqualBigIntegers",
+			a:    &object.BigInteger{Value: bigInt("123456789012345678901234567890")},
+			b:    &object.BigInteger{Value: bigInt("123456789012345678901234567890")},
+			want: true,
+		},
+		{
+			name: "UnequalBigIntegers",
+			a:    &object.BigInteger{Value: bigInt("123456789012345678901234567890")},
+			b:    &object.BigInteger{Value: bigInt("123456789012345678901234567891")},
+			want: false,
+		},
+		{name: "EqualStrings", a: str("a"), b: str("a"), want: true},
+		{name: "UnequalStrings", a: str("a"), b: str("b"), want: false},
+		{name: "EqualBooleans", a: &object.Boolean{Value: true}, b: &object.Boolean{Value: true}, want: true},
+		{name: "UnequalBooleans", a: &object.Boolean{Value: true}, b: &object.Boolean{Value: false}, want: false},
+		{name: "Nulls", a: &object.Null{}, b: &object.Null{}, want: true},
+		{name: "DifferentTypes", a: integer(1), b: str("1"), want: false},
+		{name: "EqualArrays", a: array(integer(1), str("a")), b: array(integer(1), str("a")), want: true},
+		{name: "EmptyArrays", a: array(), b: array(), want: true},
+		{name: "ArraysWithUnequalElements", a: array(integer(1), integer(2)), b: array(integer(1), integer(3)), want: false},
+		{name: "ArraysInDifferentOrder", a: array(integer(1), integer(2)), b: array(integer(2), integer(1)), want: false},
+		{name: "ArraysOfDifferentLengths", a: array(integer(1)), b: array(integer(1), integer(1)), want: false},
+		{
+			name: "EqualHashesInsertedInDifferentOrder",
+			a:    hash(str("a"), integer(1), integer(2), str("b")),
+			b:    hash(integer(2), str("b"), str("a"), integer(1)),
+			want: true,
+		},
+		{name: "EmptyHashes", a: hash(), b: hash(), want: true},
+		{name: "HashesWithUnequalValues", a: hash(str("a"), integer(1)), b: hash(str("a"), integer(2)), want: false},
+		{name: "HashesWithDifferentKeys", a: hash(str("a"), integer(1)), b: hash(str("b"), integer(1)), want: false},
+		{
+			name: "HashesOfDifferentSizes",
+			a:    hash(str("a"), integer(1)),
+			b:    hash(str("a"), integer(1), str("b"), integer(2)),
+			want: false,
+		},
+		{
+			name: "EqualNestedStructures",
+			a:    array(hash(str("a"), array(integer(1), hash())), array(array())),
+			b:    array(hash(str("a"), array(integer(1), hash())), array(array())),
+			want: true,
+		},
+		{
+			name: "UnequalNestedStructures",
+			a:    array(hash(str("a"), array(integer(1), hash()))),
+			b:    array(hash(str("a"), array(integer(1), hash(str("b"), integer(2))))),
+			want: false,
+		},
+		{name: "SameFunction", a: function, b: function, want: true},
+		{name: "DifferentFunctions", a: function, b: newFunction(), want: false},
+		{name: "SameBuiltin", a: builtin, b: builtin, want: true},
+		{name: "DifferentBuiltins", a: builtin, b: &object.Builtin{}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := object.Equals(tc.a, tc.b); got != tc.want {
+				t.Errorf("Equals(%s, %s) = %t, want %t", tc.a.Inspect(), tc.b.Inspect(), got, tc.want)
+			}
+			if got := object.Equals(tc.b, tc.a); got != tc.want {
+				t.Errorf("Equals(%s, %s) = %t, want %t", tc.b.Inspect(), tc.a.Inspect(), got, tc.want)
+			}
+		})
+	}
+}
+
+// newFunction returns a function with an empty body, which has to be non-nil for the function to be inspected.
+func newFunction() *object.Function {
+	return &object.Function{Body: &ast.BlockStatement{}}
+}
+
+func integer(value int64) *object.Integer {
+	return &object.Integer{Value: value}
+}
+
+func str(value string) *object.String {
+	return &object.String{Value: value}
+}
+
+func array(elements ...object.Object) *object.Array {
+	return &object.Array{Elements: elements}
+}
+
+// hash returns a hash containing the given keys and values, which alternate between keys and values.
+func hash(keysAndValues ...object.Object) *object.Hash {
+	pairs := map[object.HashKey]object.HashPair{}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := keysAndValues[i].(object.Hashable)
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: keysAndValues[i+1]}
+	}
+	return &object.Hash{Pairs: pairs}
+}
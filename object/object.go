@@ -0,0 +1,285 @@
+// Package object contains the types used to represent the values which Monkey programs evaluate to.
+package object
+
+import (
+	"hash/fnv"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marcuscaisey/monkey/ast"
+)
+
+// ObjectType is the type of an [Object].
+type ObjectType string
+
+const (
+	IntegerObj     ObjectType = "INTEGER"
+	BigIntegerObj  ObjectType = "BIG_INTEGER"
+	StringObj      ObjectType = "STRING"
+	BooleanObj     ObjectType = "BOOLEAN"
+	NullObj        ObjectType = "NULL"
+	ErrorObj       ObjectType = "ERROR"
+	FunctionObj    ObjectType = "FUNCTION"
+	ArrayObj       ObjectType = "ARRAY"
+	HashObj        ObjectType = "HASH"
+	BuiltinObj     ObjectType = "BUILTIN"
+	ReturnValueObj ObjectType = "RETURN_VALUE"
+	BreakObj       ObjectType = "BREAK"
+	ContinueObj    ObjectType = "CONTINUE"
+)
+
+// Object is the interface that all Monkey values implement.
+type Object interface {
+	// Type returns the type of the object.
+	Type() ObjectType
+	// Inspect returns a representation of the object's value, like the result of evaluating an expression would be
+	// printed by the REPL.
+	Inspect() string
+}
+
+// Hashable is the interface implemented by objects which can be used as the keys of a [Hash].
+type Hashable interface {
+	Object
+	// HashKey returns the key which the object is stored under in a Hash. Objects which are equal have the same key.
+	HashKey() HashKey
+}
+
+// HashKey is the key which a [Hashable] object is stored under in a [Hash]. The type of the object is included so that
+// objects of different types with the same Value, like 1 and true, are stored under different keys.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Integer is a 64-bit signed integer.
+type Integer struct {
+	Value int64
+}
+
+func (i *Integer) Type() ObjectType {
+	return IntegerObj
+}
+
+func (i *Integer) Inspect() string {
+	return strconv.FormatInt(i.Value, 10)
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+// BigInteger is an arbitrary-precision signed integer. BigIntegers are only created by an evaluator which has been
+// configured to promote integers which would overflow, so the Value of a BigInteger never fits in an [Integer].
+type BigInteger struct {
+	Value *big.Int
+}
+
+func (bi *BigInteger) Type() ObjectType {
+	return BigIntegerObj
+}
+
+func (bi *BigInteger) Inspect() string {
+	return bi.Value.String()
+}
+
+func (bi *BigInteger) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(bi.Value.String()))
+	return HashKey{Type: bi.Type(), Value: h.Sum64()}
+}
+
+// String is a string of UTF-8 encoded characters.
+type String struct {
+	Value string
+}
+
+func (s *String) Type() ObjectType {
+	return StringObj
+}
+
+// Inspect returns the value of the string without quotes.
+func (s *String) Inspect() string {
+	return s.Value
+}
+
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+// Boolean is either true or false.
+type Boolean struct {
+	Value bool
+}
+
+func (b *Boolean) Type() ObjectType {
+	return BooleanObj
+}
+
+func (b *Boolean) Inspect() string {
+	return strconv.FormatBool(b.Value)
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+// Null is the absence of a value.
+type Null struct{}
+
+func (n *Null) Type() ObjectType {
+	return NullObj
+}
+
+func (n *Null) Inspect() string {
+	return "null"
+}
+
+// BuiltinFunction is the signature of the Go functions which implement [Builtin] functions.
+type BuiltinFunction func(args ...Object) Object
+
+// Builtin is a function which is provided by the interpreter rather than being defined in Monkey.
+type Builtin struct {
+	Fn BuiltinFunction
+}
+
+func (b *Builtin) Type() ObjectType {
+	return BuiltinObj
+}
+
+func (b *Builtin) Inspect() string {
+	return "builtin function"
+}
+
+// Array is an ordered list of values.
+type Array struct {
+	Elements []Object
+}
+
+func (a *Array) Type() ObjectType {
+	return ArrayObj
+}
+
+func (a *Array) Inspect() string {
+	elements := make([]string, len(a.Elements))
+	for i, element := range a.Elements {
+		elements[i] = element.Inspect()
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Hash is a mapping from [Hashable] keys to values.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+}
+
+// HashPair is a key-value pair stored in a [Hash]. The original key is stored alongside the value so that it can be
+// retrieved from its HashKey.
+type HashPair struct {
+	Key   Hashable
+	Value Object
+}
+
+func (h *Hash) Type() ObjectType {
+	return HashObj
+}
+
+// Inspect returns the pairs of the hash sorted by their keys so that the same hash is always represented the same way.
+func (h *Hash) Inspect() string {
+	pairs := make([]string, 0, len(h.Pairs))
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// ReturnValue wraps the value of a return statement so that it can be passed up through the statements which enclose
+// the return statement and unwrapped by the function which is returning.
+type ReturnValue struct {
+	Value Object
+}
+
+func (rv *ReturnValue) Type() ObjectType {
+	return ReturnValueObj
+}
+
+func (rv *ReturnValue) Inspect() string {
+	return rv.Value.Inspect()
+}
+
+// Break is the result of a break statement, which is passed up through the statements which enclose the break
+// statement until it reaches the loop which it breaks out of. Label is the label of that loop, or empty for the
+// innermost loop.
+type Break struct {
+	Label string
+}
+
+func (b *Break) Type() ObjectType {
+	return BreakObj
+}
+
+func (b *Break) Inspect() string {
+	if b.Label == "" {
+		return "break"
+	}
+	return "break " + b.Label
+}
+
+// Continue is the result of a continue statement, which is passed up through the statements which enclose the
+// continue statement until it reaches the loop which it continues. Label is the label of that loop, or empty for the
+// innermost loop.
+type Continue struct {
+	Label string
+}
+
+func (c *Continue) Type() ObjectType {
+	return ContinueObj
+}
+
+func (c *Continue) Inspect() string {
+	if c.Label == "" {
+		return "continue"
+	}
+	return "continue " + c.Label
+}
+
+// Error is an error which occurred whilst evaluating a program.
+type Error struct {
+	Message string
+}
+
+func (e *Error) Type() ObjectType {
+	return ErrorObj
+}
+
+func (e *Error) Inspect() string {
+	return "error: " + e.Message
+}
+
+// Function is a function defined by a function literal. Env is the environment that the function was defined in, which
+// the body of the function has access to when it's called.
+type Function struct {
+	Parameters []ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (f *Function) Type() ObjectType {
+	return FunctionObj
+}
+
+func (f *Function) Inspect() string {
+	params := make([]string, len(f.Parameters))
+	for i, param := range f.Parameters {
+		params[i] = param.String()
+	}
+	return "fn(" + strings.Join(params, ", ") + ") " + f.Body.String()
+}
@@ -0,0 +1,183 @@
+package object_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/object"
+)
+
+func TestObjects(t *testing.T) {
+	testCases := []struct {
+		name        string
+		obj         object.Object
+		wantType    object.ObjectType
+		wantInspect string
+	}{
+		{
+			name:        "Integer",
+			obj:         &object.Integer{Value: 5},
+			wantType:    object.IntegerObj,
+			wantInspect: "5",
+		},
+		{
+			name:        "NegativeInteger",
+			obj:         &object.Integer{Value: -12},
+			wantType:    object.IntegerObj,
+			wantInspect: "-12",
+		},
+		{
+			name:        "BigInteger",
+			obj:         &object.BigInteger{Value: bigInt("-123456789012345678901234567890")},
+			wantType:    object.BigIntegerObj,
+			wantInspect: "-123456789012345678901234567890",
+		},
+		{
+			name:        "String",
+			obj:         &object.String{Value: "hello world"},
+			wantType:    object.StringObj,
+			wantInspect: "hello world",
+		},
+		{
+			name:        "True",
+			obj:         &object.Boolean{Value: true},
+			wantType:    object.BooleanObj,
+			wantInspect: "true",
+		},
+		{
+			name:        "False",
+			obj:         &object.Boolean{Value: false},
+			wantType:    object.BooleanObj,
+			wantInspect: "false",
+		},
+		{
+			name:        "Null",
+			obj:         &object.Null{},
+			wantType:    object.NullObj,
+			wantInspect: "null",
+		},
+		{
+			name:        "Builtin",
+			obj:         &object.Builtin{Fn: func(args ...object.Object) object.Object { return args[0] }},
+			wantType:    object.BuiltinObj,
+			wantInspect: "builtin function",
+		},
+		{
+			name: "Array",
+			obj: &object.Array{
+				Elements: []object.Object{&object.Integer{Value: 1}, &object.String{Value: "two"}, &object.Null{}},
+			},
+			wantType:    object.ArrayObj,
+			wantInspect: "[1, two, null]",
+		},
+		{
+			name:        "EmptyArray",
+			obj:         &object.Array{Elements: []object.Object{}},
+			wantType:    object.ArrayObj,
+			wantInspect: "[]",
+		},
+		{
+			name: "Hash",
+			obj: &object.Hash{
+				Pairs: map[object.HashKey]object.HashPair{
+					(&object.String{Value: "b"}).HashKey(): {
+						Key:   &object.String{Value: "b"},
+						Value: &object.Integer{Value: 2},
+					},
+					(&object.String{Value: "a"}).HashKey(): {
+						Key:   &object.String{Value: "a"},
+						Value: &object.Integer{Value: 1},
+					},
+				},
+			},
+			wantType:    object.HashObj,
+			wantInspect: "{a: 1, b: 2}",
+		},
+		{
+			name:        "ReturnValue",
+			obj:         &object.ReturnValue{Value: &object.Integer{Value: 5}},
+			wantType:    object.ReturnValueObj,
+			wantInspect: "5",
+		},
+		{
+			name:        "Error",
+			obj:         &object.Error{Message: "identifier not found: x"},
+			wantType:    object.ErrorObj,
+			wantInspect: "error: identifier not found: x",
+		},
+		{
+			name: "Function",
+			obj: &object.Function{
+				Parameters: []ast.Identifier{{Value: "x"}, {Value: "y"}},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						ast.ExpressionStatement{Expression: ast.InfixExpression{
+							Left:     ast.Identifier{Value: "x"},
+							Operator: "+",
+							Right:    ast.Identifier{Value: "y"},
+						}},
+					},
+				},
+				Env: object.NewEnvironment(),
+			},
+			wantType:    object.FunctionObj,
+			wantInspect: "fn(x, y) { (x + y); }",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.obj.Type(); got != tc.wantType {
+				t.Errorf("Type() = %q, want %q", got, tc.wantType)
+			}
+			if got := tc.obj.Inspect(); got != tc.wantInspect {
+				t.Errorf("Inspect() = %q, want %q", got, tc.wantInspect)
+			}
+		})
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	testCases := []struct {
+		name      string
+		a, b      object.Hashable
+		wantEqual bool
+	}{
+		{name: "EqualStrings", a: &object.String{Value: "hello"}, b: &object.String{Value: "hello"}, wantEqual: true},
+		{name: "DifferentStrings", a: &object.String{Value: "hello"}, b: &object.String{Value: "world"}},
+		{name: "EqualIntegers", a: &object.Integer{Value: 1}, b: &object.Integer{Value: 1}, wantEqual: true},
+		{name: "DifferentIntegers", a: &object.Integer{Value: 1}, b: &object.Integer{Value: 2}},
+		{
+			name:      "EqualBigIntegers",
+			a:         &object.BigInteger{Value: bigInt("123456789012345678901234567890")},
+			b:         &object.BigInteger{Value: bigInt("123456789012345678901234567890")},
+			wantEqual: true,
+		},
+		{
+			name: "DifferentBigIntegers",
+			a:    &object.BigInteger{Value: bigInt("123456789012345678901234567890")},
+			b:    &object.BigInteger{Value: bigInt("-123456789012345678901234567890")},
+		},
+		{name: "EqualBooleans", a: &object.Boolean{Value: true}, b: &object.Boolean{Value: true}, wantEqual: true},
+		{name: "DifferentBooleans", a: &object.Boolean{Value: true}, b: &object.Boolean{Value: false}},
+		{name: "DifferentTypes", a: &object.Integer{Value: 1}, b: &object.Boolean{Value: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := tc.a.HashKey(), tc.b.HashKey()
+			if gotEqual := a == b; gotEqual != tc.wantEqual {
+				t.Fatalf("%v.HashKey() == %v.HashKey() = %t, want %t", tc.a.Inspect(), tc.b.Inspect(), gotEqual, tc.wantEqual)
+			}
+		})
+	}
+}
+
+func bigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big integer " + s)
+	}
+	return n
+}
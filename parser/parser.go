@@ -0,0 +1,791 @@
+// Package parser contains the parser for the Monkey language. It provides a Parser struct which can be used to turn
+// the tokens from a [lexer.Lexer] into an AST (abstract syntax tree) using the nodes from the [ast] package.
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/token"
+)
+
+// precedence is the precedence of an operator. Operators with higher precedence bind more tightly to their operands.
+type precedence int
+
+const (
+	_ precedence = iota
+	lowest
+	logicalOr   // ||
+	logicalAnd  // &&
+	equals      // == or !=
+	lessGreater // <, >, <=, or >=
+	sum         // + or -
+	product     // *, /, or %
+	prefix      // -x or !x
+	call        // f(x)
+	index       // a[i]
+)
+
+// precedences maps the token types of infix operators to their precedence.
+var precedences = map[token.TokenType]precedence{
+	token.Or:           logicalOr,
+	token.And:          logicalAnd,
+	token.Equal:        equals,
+	token.NotEqual:     equals,
+	token.Less:         lessGreater,
+	token.Greater:      lessGreater,
+	token.LessEqual:    lessGreater,
+	token.GreaterEqual: lessGreater,
+	token.Plus:         sum,
+	token.Minus:        sum,
+	token.Asterisk:     product,
+	token.Slash:        product,
+	token.Percent:      product,
+	token.LParen:       call,
+	token.LBracket:     index,
+}
+
+type (
+	prefixParseFn func() ast.Expression
+	// infixParseFn is passed the already parsed expression to the left of the infix operator.
+	infixParseFn func(left ast.Expression) ast.Expression
+)
+
+// Parser parses the tokens from a [lexer.Lexer] into an AST. Expressions are parsed using Pratt parsing, where each
+// token type which can start an expression has an associated prefix parsing function and each token type which can
+// appear between two expressions has an associated infix parsing function.
+type Parser struct {
+	lexer  *lexer.Lexer
+	errors []*ParseError
+	// lexerErrCount is the number of the lexer's errors which have been recorded in errors.
+	lexerErrCount int
+	// readFailed is true once reading the source has failed, after which the lexer will keep returning the same error
+	// which has already been recorded.
+	readFailed bool
+	// comments are the comments which have been read from the lexer, if it's emitting them.
+	comments []ast.Comment
+
+	curToken  token.Token
+	peekToken token.Token
+
+	prefixParseFns map[token.TokenType]prefixParseFn
+	infixParseFns  map[token.TokenType]infixParseFn
+}
+
+// New initialises a new Parser which parses the tokens returned by the given [lexer.Lexer]. The lexer is switched into
+// the mode enabled by [lexer.Lexer.CollectErrors] so that malformed tokens are reported as parser errors. If the lexer
+// has been switched into the mode enabled by [lexer.Lexer.EmitComments], then the comments in the source are recorded
+// in [ast.Program.Comments].
+func New(l *lexer.Lexer) *Parser {
+	l.CollectErrors()
+	p := &Parser{
+		lexer: l,
+	}
+	p.prefixParseFns = map[token.TokenType]prefixParseFn{
+		token.Ident:    p.parseIdentifier,
+		token.Int:      p.parseIntegerLiteral,
+		token.String:   p.parseStringLiteral,
+		token.True:     p.parseBoolean,
+		token.False:    p.parseBoolean,
+		token.Minus:    p.parsePrefixExpression,
+		token.Bang:     p.parsePrefixExpression,
+		token.LParen:   p.parseGroupedExpression,
+		token.If:       p.parseIfExpression,
+		token.Function: p.parseFunctionLiteral,
+		token.LBracket: p.parseArrayLiteral,
+		token.LBrace:   p.parseHashLiteral,
+	}
+	p.infixParseFns = map[token.TokenType]infixParseFn{}
+	for tokenType := range precedences {
+		p.infixParseFns[tokenType] = p.parseInfixExpression
+	}
+	p.infixParseFns[token.LParen] = p.parseCallExpression
+	p.infixParseFns[token.LBracket] = p.parseIndexExpression
+	// read two tokens so that curToken and peekToken are both set
+	p.nextToken()
+	p.nextToken()
+	return p
+}
+
+// ParseProgram parses the tokens from the lexer into an [ast.Program]. Any statements which can't be parsed are left
+// out of the program and the reasons why are available from [Parser.Errors]. Parsing resumes from the end of each
+// statement which can't be parsed so that the errors in the rest of the source are also reported.
+func (p *Parser) ParseProgram() *ast.Program {
+	program := &ast.Program{
+		Statements: []ast.Statement{},
+	}
+	for p.curToken.Type != token.EOF {
+		if stmt := p.parseStatement(); stmt != nil {
+			program.Statements = append(program.Statements, stmt)
+		} else {
+			p.skipStatement()
+		}
+		p.nextToken()
+	}
+	program.Comments = p.comments
+	return program
+}
+
+// Errors returns the errors encountered whilst parsing, in the order that they were encountered.
+func (p *Parser) Errors() []*ParseError {
+	return p.errors
+}
+
+// nextToken advances the parser by one token. Illegal tokens are recorded as errors and comment tokens are recorded as
+// comments, and both are skipped over so that they're never seen by the rest of the parser.
+func (p *Parser) nextToken() {
+	p.curToken = p.peekToken
+	for {
+		if p.readFailed {
+			p.peekToken = token.Token{Type: token.EOF}
+			return
+		}
+		tok, err := p.lexer.NextToken()
+		if err != nil {
+			// the lexer is collecting errors so this can only be an error from reading the source, after which no
+			// more tokens can be read
+			p.errors = append(p.errors, &ParseError{Message: err.Error()})
+			p.readFailed = true
+			continue
+		}
+		if tok.Type == token.Comment {
+			// comment tokens are skipped whilst filling peekToken, so curToken is the token before the comment
+			trailing := p.curToken.Line == tok.Line
+			p.comments = append(p.comments, ast.Comment{Token: tok, Trailing: trailing})
+			continue
+		}
+		if tok.Type != token.Illegal {
+			p.peekToken = tok
+			return
+		}
+		if lexerErrs := p.lexer.Errors(); len(lexerErrs) > p.lexerErrCount {
+			// the illegal token is malformed source that the lexer has a more descriptive error for
+			p.errors = append(p.errors, newLexerParseError(lexerErrs[p.lexerErrCount], tok))
+			p.lexerErrCount++
+		} else {
+			p.errorf(tok, "illegal character %q", tok.Literal)
+		}
+	}
+}
+
+// parseStatement parses the statement starting at the current token. The current token is left on the last token of
+// the statement. nil is returned if the statement couldn't be parsed.
+func (p *Parser) parseStatement() ast.Statement {
+	switch p.curToken.Type {
+	case token.Let:
+		return p.parseLetStatement()
+	case token.Return:
+		return p.parseReturnStatement()
+	case token.While:
+		return p.parseWhileStatement()
+	case token.Break:
+		return p.parseBreakStatement()
+	case token.Continue:
+		return p.parseContinueStatement()
+	case token.Ident:
+		switch p.peekToken.Type {
+		case token.Assign:
+			return p.parseAssignStatement()
+		case token.Colon:
+			return p.parseLabeledStatement()
+		}
+		return p.parseExpressionStatement()
+	default:
+		return p.parseExpressionStatement()
+	}
+}
+
+// parseWhileStatement parses a statement of the form
+//
+//	while (<expression>) { <statement>... }
+func (p *Parser) parseWhileStatement() ast.Statement {
+	stmt := ast.WhileStatement{Token: p.curToken}
+	if !p.expectPeek(token.LParen) {
+		return nil
+	}
+	p.nextToken()
+	stmt.Condition = p.parseExpression(lowest)
+	if stmt.Condition == nil {
+		return nil
+	}
+	if !p.expectPeek(token.RParen) || !p.expectPeek(token.LBrace) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+	if stmt.Body == nil {
+		return nil
+	}
+	return stmt
+}
+
+// parseLabeledStatement parses a statement of the form
+//
+//	<label>: while (<expression>) { <statement>... }
+//
+// where the label can be referred to by break and continue statements inside the loop.
+func (p *Parser) parseLabeledStatement() ast.Statement {
+	label := p.curToken.Literal
+	p.nextToken()
+	if !p.expectPeek(token.While) {
+		return nil
+	}
+	stmt, ok := p.parseWhileStatement().(ast.WhileStatement)
+	if !ok {
+		return nil
+	}
+	stmt.Label = label
+	return stmt
+}
+
+// parseBreakStatement parses a statement of the form
+//
+//	break <label>;
+//
+// where the label and the trailing semicolon are optional.
+func (p *Parser) parseBreakStatement() ast.Statement {
+	stmt := ast.BreakStatement{Token: p.curToken}
+	label, ok := p.parseOptionalLabel()
+	if !ok {
+		return nil
+	}
+	stmt.Label = label
+	return stmt
+}
+
+// parseContinueStatement parses a statement of the form
+//
+//	continue <label>;
+//
+// where the label and the trailing semicolon are optional.
+func (p *Parser) parseContinueStatement() ast.Statement {
+	stmt := ast.ContinueStatement{Token: p.curToken}
+	label, ok := p.parseOptionalLabel()
+	if !ok {
+		return nil
+	}
+	stmt.Label = label
+	return stmt
+}
+
+// parseOptionalLabel parses the label which optionally follows the current token at the end of a break or continue
+// statement, along with the optional semicolon which terminates the statement. An empty label is returned if there
+// isn't one. false is returned if the statement continues with anything other than a label.
+func (p *Parser) parseOptionalLabel() (string, bool) {
+	var label string
+	switch p.peekToken.Type {
+	case token.Semicolon, token.RBrace, token.EOF:
+	default:
+		if !p.expectPeek(token.Ident) {
+			return "", false
+		}
+		label = p.curToken.Literal
+	}
+	if p.peekToken.Type == token.Semicolon {
+		p.nextToken()
+	}
+	return label, true
+}
+
+// parseBlockStatement parses a statement of the form
+//
+//	{ <statement>... }
+//
+// where the current token is the opening brace. The current token is left on the closing brace.
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken, Statements: []ast.Statement{}}
+	p.nextToken()
+	for p.curToken.Type != token.RBrace {
+		if p.curToken.Type == token.EOF {
+			p.errorf(p.curToken, "expected %s, got %s", token.RBrace, p.curToken)
+			return nil
+		}
+		if stmt := p.parseStatement(); stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		} else if p.skipStatement() {
+			// the statement was cut short by the closing brace of the block, which is the current token
+			continue
+		}
+		p.nextToken()
+	}
+	block.RBrace = p.curToken
+	return block
+}
+
+// skipStatement advances the parser to the end of a statement which couldn't be parsed. The current token is left on
+// the semicolon which terminates the statement, or on the token before the closing brace of the enclosing block or EOF
+// if the statement isn't terminated by a semicolon. Braces which are opened within the statement are skipped over so
+// that parsing doesn't resume from inside a block belonging to the statement. If the statement failed to parse on the
+// closing brace of the enclosing block, then the current token is left on that brace and true is returned so that the
+// caller can close the block there.
+func (p *Parser) skipStatement() bool {
+	depth := 0
+	for p.curToken.Type != token.EOF {
+		switch p.curToken.Type {
+		case token.LBrace:
+			depth++
+		case token.RBrace:
+			if depth == 0 {
+				return true
+			}
+			depth--
+		case token.Semicolon:
+			if depth == 0 {
+				return false
+			}
+		}
+		if depth == 0 && (p.peekToken.Type == token.RBrace || p.peekToken.Type == token.EOF) {
+			return false
+		}
+		p.nextToken()
+	}
+	return false
+}
+
+// parseLetStatement parses a statement of the form
+//
+//	let <identifier> = <expression>;
+//
+// where the trailing semicolon is optional.
+func (p *Parser) parseLetStatement() ast.Statement {
+	stmt := ast.LetStatement{Token: p.curToken}
+	if !p.expectPeek(token.Ident) {
+		return nil
+	}
+	stmt.Name = ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.Assign) {
+		return nil
+	}
+	p.nextToken()
+	stmt.Value = p.parseExpression(lowest)
+	if stmt.Value == nil {
+		return nil
+	}
+	if p.peekToken.Type == token.Semicolon {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseAssignStatement parses a statement of the form
+//
+//	<identifier> = <expression>;
+//
+// where the trailing semicolon is optional.
+func (p *Parser) parseAssignStatement() ast.Statement {
+	stmt := ast.AssignStatement{Name: ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+	p.nextToken()
+	stmt.Token = p.curToken
+	p.nextToken()
+	stmt.Value = p.parseExpression(lowest)
+	if stmt.Value == nil {
+		return nil
+	}
+	if p.peekToken.Type == token.Semicolon {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseReturnStatement parses a statement of the form
+//
+//	return <expression>;
+//
+// where the trailing semicolon is optional. The expression can be left out to return without a value, in which case
+// the ReturnValue of the statement is nil.
+func (p *Parser) parseReturnStatement() ast.Statement {
+	stmt := ast.ReturnStatement{Token: p.curToken}
+	switch p.peekToken.Type {
+	case token.Semicolon:
+		p.nextToken()
+		return stmt
+	case token.RBrace, token.EOF:
+		return stmt
+	}
+	p.nextToken()
+	stmt.ReturnValue = p.parseExpression(lowest)
+	if stmt.ReturnValue == nil {
+		return nil
+	}
+	if p.peekToken.Type == token.Semicolon {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseExpressionStatement parses a statement consisting of a single expression, optionally followed by a semicolon.
+func (p *Parser) parseExpressionStatement() ast.Statement {
+	stmt := ast.ExpressionStatement{Token: p.curToken}
+	stmt.Expression = p.parseExpression(lowest)
+	if stmt.Expression == nil {
+		return nil
+	}
+	if p.peekToken.Type == token.Assign {
+		// statements of the form <identifier> = <expression> have already been handled by parseAssignStatement
+		p.errorf(p.peekToken, "cannot assign to %s", stmt.Expression)
+		return nil
+	}
+	if p.peekToken.Type == token.Semicolon {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseExpression parses the expression starting at the current token whose operators have a higher precedence than the
+// given precedence. The current token is left on the last token of the expression. nil is returned if the expression
+// couldn't be parsed.
+func (p *Parser) parseExpression(precedence precedence) ast.Expression {
+	parsePrefix, ok := p.prefixParseFns[p.curToken.Type]
+	if !ok {
+		p.errorf(p.curToken, "expected start of expression, got %s", p.curToken)
+		return nil
+	}
+	left := parsePrefix()
+	for left != nil && p.peekPrecedence() > precedence {
+		parseInfix, ok := p.infixParseFns[p.peekToken.Type]
+		if !ok {
+			return left
+		}
+		p.nextToken()
+		left = parseInfix(left)
+	}
+	return left
+}
+
+func (p *Parser) parseIdentifier() ast.Expression {
+	return ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+}
+
+func (p *Parser) parseIntegerLiteral() ast.Expression {
+	// base 0 means that the base is derived from the prefix of the literal so that hexadecimal literals are parsed
+	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if err != nil {
+		// the lexer only returns valid integer literals so the only way that parsing can fail is if it's out of range
+		p.errorf(p.curToken, "integer literal %s overflows int64", p.curToken.Literal)
+		return nil
+	}
+	return ast.IntegerLiteral{Token: p.curToken, Value: value}
+}
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+}
+
+func (p *Parser) parseBoolean() ast.Expression {
+	return ast.Boolean{Token: p.curToken, Value: p.curToken.Type == token.True}
+}
+
+// parsePrefixExpression parses an expression of the form
+//
+//	<operator><expression>
+func (p *Parser) parsePrefixExpression() ast.Expression {
+	expr := ast.PrefixExpression{Token: p.curToken, Operator: p.curToken.Literal}
+	p.nextToken()
+	expr.Right = p.parseExpression(prefix)
+	if expr.Right == nil {
+		return nil
+	}
+	return expr
+}
+
+// parseInfixExpression parses an expression of the form
+//
+//	<expression> <operator> <expression>
+//
+// where the current token is the operator. The right expression is parsed with the precedence of the operator so that
+// operators of the same precedence are left-associative.
+func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	expr := ast.InfixExpression{Token: p.curToken, Left: left, Operator: p.curToken.Literal}
+	precedence := p.curPrecedence()
+	p.nextToken()
+	expr.Right = p.parseExpression(precedence)
+	if expr.Right == nil {
+		return nil
+	}
+	return expr
+}
+
+// parseCallExpression parses an expression of the form
+//
+//	<expression>(<expression>, <expression>, ...)
+//
+// where the current token is the opening parenthesis.
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	expr := ast.CallExpression{Token: p.curToken, Function: function}
+	expr.Arguments = p.parseExpressionList(token.RParen)
+	if expr.Arguments == nil {
+		return nil
+	}
+	return expr
+}
+
+// parseArrayLiteral parses an expression of the form
+//
+//	[<expression>, <expression>, ...]
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := ast.ArrayLiteral{Token: p.curToken}
+	array.Elements = p.parseExpressionList(token.RBracket)
+	if array.Elements == nil {
+		return nil
+	}
+	return array
+}
+
+// parseIndexExpression parses an expression of the form
+//
+//	<expression>[<expression>]
+//
+// where the current token is the opening bracket.
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expr := ast.IndexExpression{Token: p.curToken, Left: left}
+	p.nextToken()
+	expr.Index = p.parseExpression(lowest)
+	if expr.Index == nil {
+		return nil
+	}
+	if !p.expectPeek(token.RBracket) {
+		return nil
+	}
+	return expr
+}
+
+// parseHashLiteral parses an expression of the form
+//
+//	{<expression>: <expression>, <expression>: <expression>, ...}
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := ast.HashLiteral{Token: p.curToken, Pairs: []ast.HashPair{}}
+	if p.peekToken.Type == token.RBrace {
+		p.nextToken()
+		return hash
+	}
+	for {
+		p.nextToken()
+		key := p.parseExpression(lowest)
+		if key == nil {
+			return nil
+		}
+		if !p.expectPeek(token.Colon) {
+			return nil
+		}
+		p.nextToken()
+		value := p.parseExpression(lowest)
+		if value == nil {
+			return nil
+		}
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+		if p.peekToken.Type != token.Comma {
+			break
+		}
+		p.nextToken()
+	}
+	if !p.expectPeek(token.RBrace) {
+		return nil
+	}
+	return hash
+}
+
+// parseExpressionList parses a comma separated list of expressions which is terminated by the given token type. The
+// current token should be the token before the first expression and is left on the terminating token. nil is returned
+// if the list couldn't be parsed.
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	exprs := []ast.Expression{}
+	if p.peekToken.Type == end {
+		p.nextToken()
+		return exprs
+	}
+	for {
+		p.nextToken()
+		expr := p.parseExpression(lowest)
+		if expr == nil {
+			return nil
+		}
+		exprs = append(exprs, expr)
+		if p.peekToken.Type != token.Comma {
+			break
+		}
+		p.nextToken()
+	}
+	if !p.expectPeek(end) {
+		return nil
+	}
+	return exprs
+}
+
+// peekPrecedence returns the precedence of the next token, or lowest if it's not an infix operator.
+func (p *Parser) peekPrecedence() precedence {
+	if precedence, ok := precedences[p.peekToken.Type]; ok {
+		return precedence
+	}
+	return lowest
+}
+
+// curPrecedence returns the precedence of the current token, or lowest if it's not an infix operator.
+func (p *Parser) curPrecedence() precedence {
+	if precedence, ok := precedences[p.curToken.Type]; ok {
+		return precedence
+	}
+	return lowest
+}
+
+// parseGroupedExpression parses an expression of the form
+//
+//	(<expression>)
+//
+// The parentheses aren't represented in the AST since they only affect its structure.
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	p.nextToken()
+	expr := p.parseExpression(lowest)
+	if expr == nil {
+		return nil
+	}
+	if !p.expectPeek(token.RParen) {
+		return nil
+	}
+	return expr
+}
+
+// parseIfExpression parses an expression of the form
+//
+//	if (<expression>) { <statement>... } else { <statement>... }
+//
+// where the else branch is optional.
+func (p *Parser) parseIfExpression() ast.Expression {
+	expr := ast.IfExpression{Token: p.curToken}
+	if !p.expectPeek(token.LParen) {
+		return nil
+	}
+	p.nextToken()
+	expr.Condition = p.parseExpression(lowest)
+	if expr.Condition == nil {
+		return nil
+	}
+	if !p.expectPeek(token.RParen) || !p.expectPeek(token.LBrace) {
+		return nil
+	}
+	expr.Consequence = p.parseBlockStatement()
+	if expr.Consequence == nil {
+		return nil
+	}
+	if p.peekToken.Type != token.Else {
+		return expr
+	}
+	p.nextToken()
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+	expr.Alternative = p.parseBlockStatement()
+	if expr.Alternative == nil {
+		return nil
+	}
+	return expr
+}
+
+// parseFunctionLiteral parses an expression of the form
+//
+//	fn(<identifier>, <identifier>, ...) { <statement>... }
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	fn := ast.FunctionLiteral{Token: p.curToken}
+	if !p.expectPeek(token.LParen) {
+		return nil
+	}
+	fn.Parameters = p.parseFunctionParameters()
+	if fn.Parameters == nil {
+		return nil
+	}
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+	fn.Body = p.parseBlockStatement()
+	if fn.Body == nil {
+		return nil
+	}
+	return fn
+}
+
+// parseFunctionParameters parses the comma separated parameters of a function literal, where the current token is the
+// opening parenthesis. The current token is left on the closing parenthesis. nil is returned if the parameters couldn't
+// be parsed.
+func (p *Parser) parseFunctionParameters() []ast.Identifier {
+	params := []ast.Identifier{}
+	if p.peekToken.Type == token.RParen {
+		p.nextToken()
+		return params
+	}
+	for {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+		params = append(params, ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+		if p.peekToken.Type != token.Comma {
+			break
+		}
+		p.nextToken()
+	}
+	if !p.expectPeek(token.RParen) {
+		return nil
+	}
+	return params
+}
+
+// expectPeek advances the parser if the next token has the given type. Otherwise, an error is recorded. It returns
+// whether the parser was advanced.
+func (p *Parser) expectPeek(tokenType token.TokenType) bool {
+	if p.peekToken.Type != tokenType {
+		p.errorf(p.peekToken, "expected %s, got %s", tokenType, p.peekToken)
+		return false
+	}
+	p.nextToken()
+	return true
+}
+
+// errorf records an error at the position of the given token.
+func (p *Parser) errorf(tok token.Token, format string, a ...any) {
+	p.errors = append(p.errors, &ParseError{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// ParseError is an error encountered whilst parsing.
+type ParseError struct {
+	// Line and Column are the position in the source that the error occurred at. They're both 0 if the error isn't
+	// associated with a position, like when the source couldn't be read.
+	Line   int
+	Column int
+	// Message describes the error.
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// newLexerParseError converts an error returned by the lexer for the given illegal token into a [ParseError]. The
+// position of the lexer's error is used if it has one since it can be more precise than the position of the token, like
+// for an invalid escape sequence in the middle of a string literal.
+func newLexerParseError(err error, tok token.Token) *ParseError {
+	line, column := tok.Line, tok.Column
+	switch err := err.(type) {
+	case *lexer.InvalidUTF8Error:
+		line, column = err.Line, err.Column
+	case *lexer.UnterminatedCommentError:
+		line, column = err.Line, err.Column
+	case *lexer.InvalidCharError:
+		line, column = err.Line, err.Column
+	case *lexer.InvalidEscapeError:
+		line, column = err.Line, err.Column
+	case *lexer.UnterminatedStringError:
+		line, column = err.Line, err.Column
+	case *lexer.InvalidNumberError:
+		line, column = err.Line, err.Column
+	}
+	// the lexer's errors are prefixed with their position which is stored separately in the ParseError
+	message := strings.TrimPrefix(err.Error(), fmt.Sprintf("%d:%d: ", line, column))
+	return &ParseError{Line: line, Column: column, Message: message}
+}
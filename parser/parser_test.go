@@ -0,0 +1,1102 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/marcuscaisey/monkey/ast"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/parser"
+	"github.com/marcuscaisey/monkey/token"
+)
+
+// ignoreTokens ignores the tokens stored in AST nodes so that tests can compare the structure of the AST without
+// having to specify the position of every token.
+var ignoreTokens = cmpopts.IgnoreTypes(token.Token{})
+
+func TestParseProgram(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want *ast.Program
+	}{
+		{
+			name: "LetStatements",
+			src: `let x = 5;
+let y = z;
+let foobar = 0x10`,
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.LetStatement{
+						Name:  ast.Identifier{Value: "x"},
+						Value: ast.IntegerLiteral{Value: 5},
+					},
+					ast.LetStatement{
+						Name:  ast.Identifier{Value: "y"},
+						Value: ast.Identifier{Value: "z"},
+					},
+					ast.LetStatement{
+						Name:  ast.Identifier{Value: "foobar"},
+						Value: ast.IntegerLiteral{Value: 16},
+					},
+				},
+			},
+		},
+		{
+			name: "ReturnStatements",
+			src: `return 5;
+return x
+return;`,
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ReturnStatement{ReturnValue: ast.IntegerLiteral{Value: 5}},
+					ast.ReturnStatement{ReturnValue: ast.Identifier{Value: "x"}},
+					ast.ReturnStatement{ReturnValue: nil},
+				},
+			},
+		},
+		{
+			name: "AssignStatements",
+			src:  "x = 5;\ny = x + 1",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.AssignStatement{Name: ast.Identifier{Value: "x"}, Value: ast.IntegerLiteral{Value: 5}},
+					ast.AssignStatement{
+						Name: ast.Identifier{Value: "y"},
+						Value: ast.InfixExpression{
+							Left:     ast.Identifier{Value: "x"},
+							Operator: "+",
+							Right:    ast.IntegerLiteral{Value: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "WhileStatement",
+			src:  "while (x < 3) { x = x + 1; } while (true) {}",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.WhileStatement{
+						Condition: ast.InfixExpression{
+							Left:     ast.Identifier{Value: "x"},
+							Operator: "<",
+							Right:    ast.IntegerLiteral{Value: 3},
+						},
+						Body: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.AssignStatement{
+									Name: ast.Identifier{Value: "x"},
+									Value: ast.InfixExpression{
+										Left:     ast.Identifier{Value: "x"},
+										Operator: "+",
+										Right:    ast.IntegerLiteral{Value: 1},
+									},
+								},
+							},
+						},
+					},
+					ast.WhileStatement{
+						Condition: ast.Boolean{Value: true},
+						Body:      &ast.BlockStatement{Statements: []ast.Statement{}},
+					},
+				},
+			},
+		},
+		{
+			name: "LabeledWhileStatement",
+			src:  "outer: while (true) {}",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.WhileStatement{
+						Label:     "outer",
+						Condition: ast.Boolean{Value: true},
+						Body:      &ast.BlockStatement{Statements: []ast.Statement{}},
+					},
+				},
+			},
+		},
+		{
+			name: "BreakAndContinueStatements",
+			src:  "while (x) { break; continue; break outer; continue outer }",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.WhileStatement{
+						Condition: ast.Identifier{Value: "x"},
+						Body: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.BreakStatement{},
+								ast.ContinueStatement{},
+								ast.BreakStatement{Label: "outer"},
+								ast.ContinueStatement{Label: "outer"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "IntegerLiteralExpressionStatement",
+			src:  "5;",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IntegerLiteral{Value: 5}},
+				},
+			},
+		},
+		{
+			name: "IdentifierExpressionStatement",
+			src:  "foobar",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.Identifier{Value: "foobar"}},
+				},
+			},
+		},
+		{
+			name: "MaxInt64",
+			src:  "9223372036854775807; 0x7FFFFFFFFFFFFFFF",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IntegerLiteral{Value: 9223372036854775807}},
+					ast.ExpressionStatement{Expression: ast.IntegerLiteral{Value: 9223372036854775807}},
+				},
+			},
+		},
+		{
+			name: "PrefixExpressions",
+			src:  "-15; !foobar; !!5; -!x",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{
+						Expression: ast.PrefixExpression{Operator: "-", Right: ast.IntegerLiteral{Value: 15}},
+					},
+					ast.ExpressionStatement{
+						Expression: ast.PrefixExpression{Operator: "!", Right: ast.Identifier{Value: "foobar"}},
+					},
+					ast.ExpressionStatement{
+						Expression: ast.PrefixExpression{
+							Operator: "!",
+							Right:    ast.PrefixExpression{Operator: "!", Right: ast.IntegerLiteral{Value: 5}},
+						},
+					},
+					ast.ExpressionStatement{
+						Expression: ast.PrefixExpression{
+							Operator: "-",
+							Right:    ast.PrefixExpression{Operator: "!", Right: ast.Identifier{Value: "x"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Booleans",
+			src:  "true; false; !true; let x = false",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.Boolean{Value: true}},
+					ast.ExpressionStatement{Expression: ast.Boolean{Value: false}},
+					ast.ExpressionStatement{
+						Expression: ast.PrefixExpression{Operator: "!", Right: ast.Boolean{Value: true}},
+					},
+					ast.LetStatement{Name: ast.Identifier{Value: "x"}, Value: ast.Boolean{Value: false}},
+				},
+			},
+		},
+		{
+			name: "IfExpression",
+			src:  "if (x < y) { x }",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IfExpression{
+						Condition: ast.InfixExpression{
+							Left:     ast.Identifier{Value: "x"},
+							Operator: "<",
+							Right:    ast.Identifier{Value: "y"},
+						},
+						Consequence: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.ExpressionStatement{Expression: ast.Identifier{Value: "x"}},
+							},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "IfElseExpression",
+			src:  "if (x) { let y = 1; return y; } else { }",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IfExpression{
+						Condition: ast.Identifier{Value: "x"},
+						Consequence: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.LetStatement{Name: ast.Identifier{Value: "y"}, Value: ast.IntegerLiteral{Value: 1}},
+								ast.ReturnStatement{ReturnValue: ast.Identifier{Value: "y"}},
+							},
+						},
+						Alternative: &ast.BlockStatement{Statements: []ast.Statement{}},
+					}},
+				},
+			},
+		},
+		{
+			name: "NestedIfExpressions",
+			src:  "let z = if (a) { if (b) { return } } else { c };",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.LetStatement{
+						Name: ast.Identifier{Value: "z"},
+						Value: ast.IfExpression{
+							Condition: ast.Identifier{Value: "a"},
+							Consequence: &ast.BlockStatement{
+								Statements: []ast.Statement{
+									ast.ExpressionStatement{Expression: ast.IfExpression{
+										Condition: ast.Identifier{Value: "b"},
+										Consequence: &ast.BlockStatement{
+											Statements: []ast.Statement{ast.ReturnStatement{}},
+										},
+									}},
+								},
+							},
+							Alternative: &ast.BlockStatement{
+								Statements: []ast.Statement{
+									ast.ExpressionStatement{Expression: ast.Identifier{Value: "c"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "FunctionLiterals",
+			src:  "fn() {}; fn(x) { x }; fn(x, y) { let z = x + y; return z; }",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.FunctionLiteral{
+						Parameters: []ast.Identifier{},
+						Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
+					}},
+					ast.ExpressionStatement{Expression: ast.FunctionLiteral{
+						Parameters: []ast.Identifier{{Value: "x"}},
+						Body: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.ExpressionStatement{Expression: ast.Identifier{Value: "x"}},
+							},
+						},
+					}},
+					ast.ExpressionStatement{Expression: ast.FunctionLiteral{
+						Parameters: []ast.Identifier{{Value: "x"}, {Value: "y"}},
+						Body: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.LetStatement{
+									Name: ast.Identifier{Value: "z"},
+									Value: ast.InfixExpression{
+										Left:     ast.Identifier{Value: "x"},
+										Operator: "+",
+										Right:    ast.Identifier{Value: "y"},
+									},
+								},
+								ast.ReturnStatement{ReturnValue: ast.Identifier{Value: "z"}},
+							},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "CallExpressions",
+			src:  "f(); add(1, 2 * 3, g(x)); fn(x) { x }(5)",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.CallExpression{
+						Function:  ast.Identifier{Value: "f"},
+						Arguments: []ast.Expression{},
+					}},
+					ast.ExpressionStatement{Expression: ast.CallExpression{
+						Function: ast.Identifier{Value: "add"},
+						Arguments: []ast.Expression{
+							ast.IntegerLiteral{Value: 1},
+							ast.InfixExpression{
+								Left:     ast.IntegerLiteral{Value: 2},
+								Operator: "*",
+								Right:    ast.IntegerLiteral{Value: 3},
+							},
+							ast.CallExpression{
+								Function:  ast.Identifier{Value: "g"},
+								Arguments: []ast.Expression{ast.Identifier{Value: "x"}},
+							},
+						},
+					}},
+					ast.ExpressionStatement{Expression: ast.CallExpression{
+						Function: ast.FunctionLiteral{
+							Parameters: []ast.Identifier{{Value: "x"}},
+							Body: &ast.BlockStatement{
+								Statements: []ast.Statement{
+									ast.ExpressionStatement{Expression: ast.Identifier{Value: "x"}},
+								},
+							},
+						},
+						Arguments: []ast.Expression{ast.IntegerLiteral{Value: 5}},
+					}},
+				},
+			},
+		},
+		{
+			name: "StringLiterals",
+			src:  `"hello world"; "a\tb"; ` + "`raw\\n`" + `; let s = "x" + ""`,
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.StringLiteral{Value: "hello world"}},
+					ast.ExpressionStatement{Expression: ast.StringLiteral{Value: "a\tb"}},
+					ast.ExpressionStatement{Expression: ast.StringLiteral{Value: `raw\n`}},
+					ast.LetStatement{
+						Name: ast.Identifier{Value: "s"},
+						Value: ast.InfixExpression{
+							Left:     ast.StringLiteral{Value: "x"},
+							Operator: "+",
+							Right:    ast.StringLiteral{Value: ""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ArrayLiterals",
+			src:  `[]; [1, 2 * 2, "three", fn(x) { x }, [f(5)]]`,
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.ArrayLiteral{Elements: []ast.Expression{}}},
+					ast.ExpressionStatement{Expression: ast.ArrayLiteral{
+						Elements: []ast.Expression{
+							ast.IntegerLiteral{Value: 1},
+							ast.InfixExpression{
+								Left:     ast.IntegerLiteral{Value: 2},
+								Operator: "*",
+								Right:    ast.IntegerLiteral{Value: 2},
+							},
+							ast.StringLiteral{Value: "three"},
+							ast.FunctionLiteral{
+								Parameters: []ast.Identifier{{Value: "x"}},
+								Body: &ast.BlockStatement{
+									Statements: []ast.Statement{
+										ast.ExpressionStatement{Expression: ast.Identifier{Value: "x"}},
+									},
+								},
+							},
+							ast.ArrayLiteral{
+								Elements: []ast.Expression{
+									ast.CallExpression{
+										Function:  ast.Identifier{Value: "f"},
+										Arguments: []ast.Expression{ast.IntegerLiteral{Value: 5}},
+									},
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "IndexExpressions",
+			src:  "myArray[1 + 1]; a[0][1]",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IndexExpression{
+						Left: ast.Identifier{Value: "myArray"},
+						Index: ast.InfixExpression{
+							Left:     ast.IntegerLiteral{Value: 1},
+							Operator: "+",
+							Right:    ast.IntegerLiteral{Value: 1},
+						},
+					}},
+					ast.ExpressionStatement{Expression: ast.IndexExpression{
+						Left: ast.IndexExpression{
+							Left:  ast.Identifier{Value: "a"},
+							Index: ast.IntegerLiteral{Value: 0},
+						},
+						Index: ast.IntegerLiteral{Value: 1},
+					}},
+				},
+			},
+		},
+		{
+			name: "HashLiterals",
+			src:  `{}; {"one": 1, "two": 1 + 1}; {1: true, x: [2]}`,
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.HashLiteral{Pairs: []ast.HashPair{}}},
+					ast.ExpressionStatement{Expression: ast.HashLiteral{
+						Pairs: []ast.HashPair{
+							{Key: ast.StringLiteral{Value: "one"}, Value: ast.IntegerLiteral{Value: 1}},
+							{
+								Key: ast.StringLiteral{Value: "two"},
+								Value: ast.InfixExpression{
+									Left:     ast.IntegerLiteral{Value: 1},
+									Operator: "+",
+									Right:    ast.IntegerLiteral{Value: 1},
+								},
+							},
+						},
+					}},
+					ast.ExpressionStatement{Expression: ast.HashLiteral{
+						Pairs: []ast.HashPair{
+							{Key: ast.IntegerLiteral{Value: 1}, Value: ast.Boolean{Value: true}},
+							{
+								Key:   ast.Identifier{Value: "x"},
+								Value: ast.ArrayLiteral{Elements: []ast.Expression{ast.IntegerLiteral{Value: 2}}},
+							},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "InfixExpressions",
+			src:  "a + b; a - b; a * b; a / b; a % b; a == b; a != b; a < b; a > b; a <= b; a >= b; a && b; a || b",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					infixStatement("+"),
+					infixStatement("-"),
+					infixStatement("*"),
+					infixStatement("/"),
+					infixStatement("%"),
+					infixStatement("=="),
+					infixStatement("!="),
+					infixStatement("<"),
+					infixStatement(">"),
+					infixStatement("<="),
+					infixStatement(">="),
+					infixStatement("&&"),
+					infixStatement("||"),
+				},
+			},
+		},
+		{
+			name: "OperatorPrecedence",
+			src:  "1 + 2 * 3; a - b - c; -a * b; a || b && c == d < e + f",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.InfixExpression{
+						Left:     ast.IntegerLiteral{Value: 1},
+						Operator: "+",
+						Right: ast.InfixExpression{
+							Left:     ast.IntegerLiteral{Value: 2},
+							Operator: "*",
+							Right:    ast.IntegerLiteral{Value: 3},
+						},
+					}},
+					ast.ExpressionStatement{Expression: ast.InfixExpression{
+						Left: ast.InfixExpression{
+							Left:     ast.Identifier{Value: "a"},
+							Operator: "-",
+							Right:    ast.Identifier{Value: "b"},
+						},
+						Operator: "-",
+						Right:    ast.Identifier{Value: "c"},
+					}},
+					ast.ExpressionStatement{Expression: ast.InfixExpression{
+						Left:     ast.PrefixExpression{Operator: "-", Right: ast.Identifier{Value: "a"}},
+						Operator: "*",
+						Right:    ast.Identifier{Value: "b"},
+					}},
+					ast.ExpressionStatement{Expression: ast.InfixExpression{
+						Left:     ast.Identifier{Value: "a"},
+						Operator: "||",
+						Right: ast.InfixExpression{
+							Left:     ast.Identifier{Value: "b"},
+							Operator: "&&",
+							Right: ast.InfixExpression{
+								Left:     ast.Identifier{Value: "c"},
+								Operator: "==",
+								Right: ast.InfixExpression{
+									Left:     ast.Identifier{Value: "d"},
+									Operator: "<",
+									Right: ast.InfixExpression{
+										Left:     ast.Identifier{Value: "e"},
+										Operator: "+",
+										Right:    ast.Identifier{Value: "f"},
+									},
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.src))
+			got := p.ParseProgram()
+			if errs := p.Errors(); len(errs) > 0 {
+				t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", tc.src, errs)
+			}
+			if diff := cmp.Diff(tc.want, got, ignoreTokens); diff != "" {
+				t.Fatalf("ParseProgram() returned incorrect AST for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestParseProgramOperatorPrecedence(t *testing.T) {
+	testCases := []struct {
+		src  string
+		want string
+	}{
+		{src: "-a * b", want: "((-a) * b);"},
+		{src: "!-a", want: "(!(-a));"},
+		{src: "a + b + c", want: "((a + b) + c);"},
+		{src: "a + b - c", want: "((a + b) - c);"},
+		{src: "a * b * c", want: "((a * b) * c);"},
+		{src: "a * b / c", want: "((a * b) / c);"},
+		{src: "a + b / c", want: "(a + (b / c));"},
+		{src: "a + b % c", want: "(a + (b % c));"},
+		{src: "a + b * c + d / e - f", want: "(((a + (b * c)) + (d / e)) - f);"},
+		{src: "3 + 4; -5 * 5", want: "(3 + 4);\n((-5) * 5);"},
+		{src: "5 > 4 == 3 < 4", want: "((5 > 4) == (3 < 4));"},
+		{src: "5 <= 4 != 3 >= 4", want: "((5 <= 4) != (3 >= 4));"},
+		{src: "3 + 4 * 5 == 3 * 1 + 4 * 5", want: "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)));"},
+		{src: "a == b && c != d || e", want: "(((a == b) && (c != d)) || e);"},
+		{src: "a || b && c", want: "(a || (b && c));"},
+		{src: "3 > 5 == false", want: "((3 > 5) == false);"},
+		{src: "!true != false", want: "((!true) != false);"},
+		{src: "1 + (2 + 3) + 4", want: "((1 + (2 + 3)) + 4);"},
+		{src: "(5 + 5) * 2", want: "((5 + 5) * 2);"},
+		{src: "2 / (5 + 5)", want: "(2 / (5 + 5));"},
+		{src: "-(5 + 5)", want: "(-(5 + 5));"},
+		{src: "!(true == true)", want: "(!(true == true));"},
+		{src: "((a))", want: "a;"},
+		{src: `"hello world";`, want: `"hello world";`},
+		{src: "[1, 2 * 3, [a + b]]", want: "[1, (2 * 3), [(a + b)]];"},
+		{src: "myArray[1 + 1]", want: "(myArray[(1 + 1)]);"},
+		{src: "a[0][1]", want: "((a[0])[1]);"},
+		{src: "a * [1, 2, 3, 4][b * c] * d", want: "((a * ([1, 2, 3, 4][(b * c)])) * d);"},
+		{src: "add(a * b[2], b[1], 2 * [1, 2][1])", want: "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));"},
+		{src: "-a[0]", want: "(-(a[0]));"},
+		{src: "f(x)[0]", want: "(f(x)[0]);"},
+		{src: `{"b": 2, "a": 1 * 3}`, want: `{"b": 2, "a": (1 * 3)};`},
+		{src: `{"a": 1}["a"]`, want: `({"a": 1}["a"]);`},
+		{src: "x = y == 1 + 2", want: "x = (y == (1 + 2));"},
+		{src: `"a\"b\\c\nd" + ` + "`e\\f`", want: `("a\"b\\c\nd" + "e\\f");`},
+		{src: "a + add(b * c) + d", want: "((a + add((b * c))) + d);"},
+		{src: "add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))", want: "add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)));"},
+		{src: "add(a + b + c * d / f + g)", want: "add((((a + b) + ((c * d) / f)) + g));"},
+		{src: "-f(x)", want: "(-f(x));"},
+		{src: "fn(a, b) { a * b + 1 }", want: "fn(a, b) { ((a * b) + 1); };"},
+		{src: "if (a > b) { a + 1 } else { b; c }", want: "if ((a > b)) { (a + 1); } else { b; c; };"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.src))
+			program := p.ParseProgram()
+			if errs := p.Errors(); len(errs) > 0 {
+				t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", tc.src, errs)
+			}
+			if got := program.String(); got != tc.want {
+				t.Fatalf("ParseProgram().String() = %q for source %q, want %q", got, tc.src, tc.want)
+			}
+		})
+	}
+}
+
+// infixStatement returns an expression statement containing the infix expression a <operator> b.
+func infixStatement(operator string) ast.ExpressionStatement {
+	return ast.ExpressionStatement{Expression: ast.InfixExpression{
+		Left:     ast.Identifier{Value: "a"},
+		Operator: operator,
+		Right:    ast.Identifier{Value: "b"},
+	}}
+}
+
+func TestParseProgramReturnStatementTokenLiteral(t *testing.T) {
+	src := "return 5;"
+	program := parser.New(lexer.New(src)).ParseProgram()
+	if len(program.Statements) != 1 {
+		t.Fatalf("ParseProgram() returned %d statements for source %q, want 1", len(program.Statements), src)
+	}
+	stmt, ok := program.Statements[0].(ast.ReturnStatement)
+	if !ok {
+		t.Fatalf("ParseProgram() returned %T for source %q, want ast.ReturnStatement", program.Statements[0], src)
+	}
+	if got := stmt.TokenLiteral(); got != "return" {
+		t.Fatalf("TokenLiteral() = %q for source %q, want %q", got, src, "return")
+	}
+}
+
+func TestParseProgramBooleanTokenLiteral(t *testing.T) {
+	for _, src := range []string{"true;", "false;"} {
+		program := parser.New(lexer.New(src)).ParseProgram()
+		if len(program.Statements) != 1 {
+			t.Fatalf("ParseProgram() returned %d statements for source %q, want 1", len(program.Statements), src)
+		}
+		stmt, ok := program.Statements[0].(ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("ParseProgram() returned %T for source %q, want ast.ExpressionStatement", program.Statements[0], src)
+		}
+		want := src[:len(src)-1]
+		if got := stmt.Expression.TokenLiteral(); got != want {
+			t.Fatalf("TokenLiteral() = %q for source %q, want %q", got, src, want)
+		}
+	}
+}
+
+func TestParseProgramRecordsErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "LetMissingIdentifier",
+			src:  "let = 5;",
+			want: `1:5: expected IDENT, got ASSIGN("=")`,
+		},
+		{
+			name: "LetMissingAssign",
+			src:  "let x 5",
+			want: `1:7: expected ASSIGN, got INT("5")`,
+		},
+		{
+			name: "LetMissingValue",
+			src:  "let x =",
+			want: "1:8: expected start of expression, got EOF",
+		},
+		{
+			name: "UnclosedParen",
+			src:  "(1 + 2;",
+			want: `1:7: expected R_PAREN, got SEMICOLON(";")`,
+		},
+		{
+			name: "UnclosedParenAtEOF",
+			src:  "(1 + 2",
+			want: "1:7: expected R_PAREN, got EOF",
+		},
+		{
+			name: "UnopenedParen",
+			src:  "1 + 2)",
+			want: `1:6: expected start of expression, got R_PAREN(")")`,
+		},
+		{
+			name: "IfMissingParens",
+			src:  "if x { y }",
+			want: `1:4: expected L_PAREN, got IDENT("x")`,
+		},
+		{
+			name: "IfMissingBrace",
+			src:  "if (x) y",
+			want: `1:8: expected L_BRACE, got IDENT("y")`,
+		},
+		{
+			name: "IfUnclosedBlock",
+			src:  "if (x) { y",
+			want: "1:11: expected R_BRACE, got EOF",
+		},
+		{
+			name: "ElseMissingBrace",
+			src:  "if (x) { y } else z",
+			want: `1:19: expected L_BRACE, got IDENT("z")`,
+		},
+		{
+			name: "WhileMissingParens",
+			src:  "while x { y }",
+			want: `1:7: expected L_PAREN, got IDENT("x")`,
+		},
+		{
+			name: "WhileMissingBrace",
+			src:  "while (x) y",
+			want: `1:11: expected L_BRACE, got IDENT("y")`,
+		},
+		{
+			name: "LabelMissingWhile",
+			src:  "outer: x",
+			want: `1:8: expected WHILE, got IDENT("x")`,
+		},
+		{
+			name: "BreakInvalidLabel",
+			src:  "break 1",
+			want: `1:7: expected IDENT, got INT("1")`,
+		},
+		{
+			name: "ContinueInvalidLabel",
+			src:  "continue 1",
+			want: `1:10: expected IDENT, got INT("1")`,
+		},
+		{
+			name: "FunctionMissingParens",
+			src:  "fn x { x }",
+			want: `1:4: expected L_PAREN, got IDENT("x")`,
+		},
+		{
+			name: "FunctionUnclosedParameters",
+			src:  "fn(x, y { x }",
+			want: `1:9: expected R_PAREN, got L_BRACE("{")`,
+		},
+		{
+			name: "FunctionTrailingComma",
+			src:  "fn(x,) { x }",
+			want: `1:6: expected IDENT, got R_PAREN(")")`,
+		},
+		{
+			name: "FunctionMissingBody",
+			src:  "fn(x) x",
+			want: `1:7: expected L_BRACE, got IDENT("x")`,
+		},
+		{
+			name: "CallTrailingComma",
+			src:  "add(1, 2,)",
+			want: `1:10: expected start of expression, got R_PAREN(")")`,
+		},
+		{
+			name: "CallUnclosedArguments",
+			src:  "add(1, 2",
+			want: "1:9: expected R_PAREN, got EOF",
+		},
+		{
+			name: "ArrayUnclosed",
+			src:  "[1, 2",
+			want: "1:6: expected R_BRACKET, got EOF",
+		},
+		{
+			name: "ArrayMissingComma",
+			src:  "[1 2]",
+			want: `1:4: expected R_BRACKET, got INT("2")`,
+		},
+		{
+			name: "IndexUnclosed",
+			src:  "a[1",
+			want: "1:4: expected R_BRACKET, got EOF",
+		},
+		{
+			name: "IndexMissing",
+			src:  "a[]",
+			want: `1:3: expected start of expression, got R_BRACKET("]")`,
+		},
+		{
+			name: "HashMissingColon",
+			src:  `{"a" 1}`,
+			want: `1:6: expected COLON, got INT("1")`,
+		},
+		{
+			name: "HashUnclosed",
+			src:  `{"a": 1, "b": 2`,
+			want: "1:16: expected R_BRACE, got EOF",
+		},
+		{
+			name: "HashMissingValue",
+			src:  `{"a": }`,
+			want: `1:7: expected start of expression, got R_BRACE("}")`,
+		},
+		{
+			name: "AssignToInteger",
+			src:  "5 = 3",
+			want: "1:3: cannot assign to 5",
+		},
+		{
+			name: "AssignToExpression",
+			src:  "x + 1 = 3",
+			want: "1:7: cannot assign to (x + 1)",
+		},
+		{
+			name: "AssignMissingValue",
+			src:  "x = ;",
+			want: `1:5: expected start of expression, got SEMICOLON(";")`,
+		},
+		{
+			name: "IntegerOverflow",
+			src:  "let x = 1;\n  9223372036854775808;",
+			want: "2:3: integer literal 9223372036854775808 overflows int64",
+		},
+		{
+			name: "HexadecimalIntegerOverflow",
+			src:  "0x8000000000000000",
+			want: "1:1: integer literal 0x8000000000000000 overflows int64",
+		},
+		{
+			name: "IllegalCharacter",
+			src:  `let x = \`,
+			want: `1:9: illegal character "\\"`,
+		},
+		{
+			name: "LexerError",
+			src:  `let x = 1__0`,
+			want: `1:9: invalid number literal "1__": consecutive underscores`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.src))
+			p.ParseProgram()
+			// only the first error is checked since the parser may record further errors from the tokens which follow
+			// the malformed statement
+			errs := p.Errors()
+			if len(errs) == 0 {
+				t.Fatalf("ParseProgram() recorded no errors for source %q, want %q", tc.src, tc.want)
+			}
+			if got := errs[0].Error(); got != tc.want {
+				t.Fatalf("ParseProgram() recorded first error %q for source %q, want %q", got, tc.src, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseProgramErrorPositions(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want *parser.ParseError
+	}{
+		{
+			name: "ExpectedToken",
+			src:  "let x = 1;\nlet y 2;",
+			want: &parser.ParseError{Line: 2, Column: 7, Message: `expected ASSIGN, got INT("2")`},
+		},
+		{
+			name: "ExpectedExpression",
+			src:  "let x = 1;\n\nreturn\n  * 2",
+			want: &parser.ParseError{Line: 4, Column: 3, Message: `expected start of expression, got ASTERISK("*")`},
+		},
+		{
+			name: "ExpectedTokenAtEOF",
+			src:  "if (x) {\n  y",
+			want: &parser.ParseError{Line: 2, Column: 4, Message: "expected R_BRACE, got EOF"},
+		},
+		{
+			name: "LexerErrorWithinToken",
+			src:  "let x = \"ab\\qc\"",
+			want: &parser.ParseError{Line: 1, Column: 13, Message: `invalid escape sequence "\\q"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.src))
+			p.ParseProgram()
+			errs := p.Errors()
+			if len(errs) == 0 {
+				t.Fatalf("ParseProgram() recorded no errors for source %q, want %v", tc.src, tc.want)
+			}
+			if diff := cmp.Diff(tc.want, errs[0]); diff != "" {
+				t.Fatalf("ParseProgram() recorded incorrect first error for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestParseProgramRecordsReadErrorOnce(t *testing.T) {
+	src := iotest.ErrReader(errors.New("read failed"))
+	want := []*parser.ParseError{{Message: "read failed"}}
+
+	p := parser.New(lexer.NewReader(src))
+	p.ParseProgram()
+
+	if diff := cmp.Diff(want, p.Errors()); diff != "" {
+		t.Fatalf("ParseProgram() recorded incorrect errors\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+}
+
+func TestParseProgramRecordsComments(t *testing.T) {
+	src := "// doc\nlet x = 1; // trailing\nif (x) { /* inside */\n  x\n}\n/* last */"
+	wantStatements := []ast.Statement{
+		ast.LetStatement{Name: ast.Identifier{Value: "x"}, Value: ast.IntegerLiteral{Value: 1}},
+		ast.ExpressionStatement{Expression: ast.IfExpression{
+			Condition: ast.Identifier{Value: "x"},
+			Consequence: &ast.BlockStatement{
+				Statements: []ast.Statement{ast.ExpressionStatement{Expression: ast.Identifier{Value: "x"}}},
+			},
+		}},
+	}
+	wantComments := []ast.Comment{
+		{Token: token.Token{Type: token.Comment, Literal: "// doc", Line: 1, Column: 1}},
+		{Token: token.Token{Type: token.Comment, Literal: "// trailing", Line: 2, Column: 12}, Trailing: true},
+		{Token: token.Token{Type: token.Comment, Literal: "/* inside */", Line: 3, Column: 10}, Trailing: true},
+		{Token: token.Token{Type: token.Comment, Literal: "/* last */", Line: 6, Column: 1}},
+	}
+
+	l := lexer.New(src)
+	l.EmitComments()
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", src, errs)
+	}
+	if diff := cmp.Diff(wantStatements, program.Statements, ignoreTokens); diff != "" {
+		t.Errorf("ParseProgram() returned incorrect statements for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+	if diff := cmp.Diff(wantComments, program.Comments); diff != "" {
+		t.Errorf("ParseProgram() returned incorrect comments for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestParseProgramDoesNotRecordCommentsByDefault(t *testing.T) {
+	src := "// doc\nlet x = 1; // trailing"
+
+	program := parser.New(lexer.New(src)).ParseProgram()
+
+	if len(program.Comments) > 0 {
+		t.Fatalf("ParseProgram() returned comments %v for source %q, want none", program.Comments, src)
+	}
+}
+
+func TestParseProgramRecordsClosingBraceOfBlock(t *testing.T) {
+	src := "fn() {\n  x\n}"
+	want := token.Token{Type: token.RBrace, Literal: "}", Line: 3, Column: 1}
+
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", src, errs)
+	}
+	body := program.Statements[0].(ast.ExpressionStatement).Expression.(ast.FunctionLiteral).Body
+	if diff := cmp.Diff(want, body.RBrace); diff != "" {
+		t.Fatalf("ParseProgram() recorded incorrect closing brace for source %q\ndiff:\n--- want\n+++ got\n%s", src, diff)
+	}
+}
+
+func TestParseProgramRecoversFromErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		src        string
+		want       *ast.Program
+		wantErrors []string
+	}{
+		{
+			name: "MalformedStatementBetweenStatements",
+			src:  "let x = 1;\nlet y 2 + 3;\nlet z = 4;",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.LetStatement{Name: ast.Identifier{Value: "x"}, Value: ast.IntegerLiteral{Value: 1}},
+					ast.LetStatement{Name: ast.Identifier{Value: "z"}, Value: ast.IntegerLiteral{Value: 4}},
+				},
+			},
+			wantErrors: []string{`2:7: expected ASSIGN, got INT("2")`},
+		},
+		{
+			name: "MalformedStatementContainingBlock",
+			src:  "fn(x y) { x; y; };\nz",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.Identifier{Value: "z"}},
+				},
+			},
+			wantErrors: []string{`1:6: expected R_PAREN, got IDENT("y")`},
+		},
+		{
+			name: "MalformedStatementInsideBlock",
+			src:  "if (x) { let = 1; y } else { let z }\nw",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IfExpression{
+						Condition: ast.Identifier{Value: "x"},
+						Consequence: &ast.BlockStatement{
+							Statements: []ast.Statement{
+								ast.ExpressionStatement{Expression: ast.Identifier{Value: "y"}},
+							},
+						},
+						Alternative: &ast.BlockStatement{Statements: []ast.Statement{}},
+					}},
+					ast.ExpressionStatement{Expression: ast.Identifier{Value: "w"}},
+				},
+			},
+			wantErrors: []string{
+				`1:14: expected IDENT, got ASSIGN("=")`,
+				`1:36: expected ASSIGN, got R_BRACE("}")`,
+			},
+		},
+		{
+			name: "IncompleteExpressionAtEndOfIfBlock",
+			src:  "if (x) { y + }\nlet a = 1;\nlet b = 2;",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.IfExpression{
+						Condition:   ast.Identifier{Value: "x"},
+						Consequence: &ast.BlockStatement{Statements: []ast.Statement{}},
+					}},
+					ast.LetStatement{Name: ast.Identifier{Value: "a"}, Value: ast.IntegerLiteral{Value: 1}},
+					ast.LetStatement{Name: ast.Identifier{Value: "b"}, Value: ast.IntegerLiteral{Value: 2}},
+				},
+			},
+			wantErrors: []string{`1:14: expected start of expression, got R_BRACE("}")`},
+		},
+		{
+			name: "IncompleteExpressionAtEndOfFunctionBody",
+			src:  "fn() { return x * };\nlet a = 1;",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.FunctionLiteral{
+						Parameters: []ast.Identifier{},
+						Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
+					}},
+					ast.LetStatement{Name: ast.Identifier{Value: "a"}, Value: ast.IntegerLiteral{Value: 1}},
+				},
+			},
+			wantErrors: []string{`1:19: expected start of expression, got R_BRACE("}")`},
+		},
+		{
+			name: "IncompleteExpressionAtEndOfWhileBody",
+			src:  "while (x) { x = }\nlet a = 1;",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.WhileStatement{
+						Condition: ast.Identifier{Value: "x"},
+						Body:      &ast.BlockStatement{Statements: []ast.Statement{}},
+					},
+					ast.LetStatement{Name: ast.Identifier{Value: "a"}, Value: ast.IntegerLiteral{Value: 1}},
+				},
+			},
+			wantErrors: []string{`1:17: expected start of expression, got R_BRACE("}")`},
+		},
+		{
+			name: "IncompleteExpressionBeforeUnmatchedBrace",
+			src:  "y + }\nlet a = 1;",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.LetStatement{Name: ast.Identifier{Value: "a"}, Value: ast.IntegerLiteral{Value: 1}},
+				},
+			},
+			wantErrors: []string{`1:5: expected start of expression, got R_BRACE("}")`},
+		},
+		{
+			name: "MultipleMalformedStatements",
+			src:  "let = 1; a; -; b",
+			want: &ast.Program{
+				Statements: []ast.Statement{
+					ast.ExpressionStatement{Expression: ast.Identifier{Value: "a"}},
+					ast.ExpressionStatement{Expression: ast.Identifier{Value: "b"}},
+				},
+			},
+			wantErrors: []string{
+				`1:5: expected IDENT, got ASSIGN("=")`,
+				`1:14: expected start of expression, got SEMICOLON(";")`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.src))
+			got := p.ParseProgram()
+			if diff := cmp.Diff(tc.want, got, ignoreTokens); diff != "" {
+				t.Errorf("ParseProgram() returned incorrect AST for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+			gotErrors := make([]string, len(p.Errors()))
+			for i, err := range p.Errors() {
+				gotErrors[i] = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErrors, gotErrors); diff != "" {
+				t.Errorf("ParseProgram() recorded incorrect errors for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
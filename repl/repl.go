@@ -5,24 +5,227 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
+	"github.com/marcuscaisey/monkey/evaluator"
 	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
 	"github.com/marcuscaisey/monkey/token"
 )
 
-// Start starts the REPL, reading input from the given [io.Reader] and writing output to the given [io.Writer].
+const (
+	prompt             = "> "
+	continuationPrompt = "... "
+)
+
+const help = `Enter Monkey source code to evaluate it, or one of the following commands:
+  :help                  print this help
+  :env                   print the current bindings
+  :reset                 clear the current bindings
+  :complete [prefix]     print the keywords, builtins, and bindings starting with prefix
+  :mode lex|parse|eval   print the tokens, AST, or value of each input (default eval)
+  :quit, :exit           exit the REPL
+`
+
+// outputMode determines what's printed for each input.
+type outputMode string
+
+const (
+	lexMode   outputMode = "lex"   // print the tokens of the input
+	parseMode outputMode = "parse" // print the AST of the input
+	evalMode  outputMode = "eval"  // print the value of the input
+)
+
+// Start starts the REPL, reading input from the given [io.Reader] and writing output to the given [io.Writer]. By
+// default, each input is evaluated and the resulting value is printed. The :mode command switches to printing the
+// tokens or the AST of each input instead. Bindings created by one input are visible to the inputs which
+// follow it, until the :reset command is entered which clears all bindings.
+//
+// An input which is incomplete, like a function literal whose closing brace hasn't been entered yet, is continued on
+// the following lines until it's complete. Entering an empty line ends the input even if it's incomplete.
+//
+// Lines starting with a colon are commands rather than Monkey source code. Start returns when the end of the input is
+// reached or the :quit or :exit command is entered. See the :help command for the full list of commands.
 func Start(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
+	eval := evaluator.New(out, &scannerReader{scanner: scanner})
+	env := object.NewEnvironment()
+	mode := evalMode
 
 	for {
-		fmt.Fprint(out, "> ")
+		fmt.Fprint(out, prompt)
 		if !scanner.Scan() {
 			return
 		}
 		line := scanner.Text()
-		lexer := lexer.New(line)
-		for tok := lexer.NextToken(); tok.Type != token.EOF; tok = lexer.NextToken() {
-			fmt.Fprintf(out, "%+v\n", tok)
+		if command := strings.TrimSpace(line); strings.HasPrefix(command, ":") {
+			args := strings.Fields(command)
+			switch command = args[0]; command {
+			case ":quit", ":exit":
+				return
+			case ":help":
+				fmt.Fprint(out, help)
+			case ":env":
+				for _, name := range env.Names() {
+					val, _ := env.Get(name)
+					fmt.Fprintf(out, "%s = %s\n", name, val.Inspect())
+				}
+			case ":reset":
+				env = object.NewEnvironment()
+			case ":complete":
+				prefix := ""
+				if len(args) > 1 {
+					prefix = args[1]
+				}
+				for _, name := range completions(eval, env, prefix) {
+					fmt.Fprintln(out, name)
+				}
+			case ":mode":
+				if len(args) != 2 {
+					fmt.Fprintf(out, "mode: %s\n", mode)
+					break
+				}
+				switch newMode := outputMode(args[1]); newMode {
+				case lexMode, parseMode, evalMode:
+					mode = newMode
+				default:
+					fmt.Fprintf(out, "error: unknown mode %s, want one of lex, parse, or eval\n", args[1])
+				}
+			default:
+				fmt.Fprintf(out, "error: unknown command %s, enter :help for the list of commands\n", command)
+			}
+			continue
+		}
+		src, ok := readContinuationLines(scanner, out, line)
+		if !ok {
+			return
+		}
+		if mode == lexMode {
+			printTokens(out, src)
+			continue
+		}
+		p := parser.New(lexer.New(src))
+		program := p.ParseProgram()
+		if errs := p.Errors(); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintf(out, "error: %s\n", err)
+			}
+			continue
+		}
+		if mode == parseMode {
+			fmt.Fprintln(out, program)
+			continue
+		}
+		fmt.Fprintln(out, eval.Eval(program, env).Inspect())
+	}
+}
+
+// completions returns the sorted keywords, builtin names, and names bound in the environment which start with the given
+// prefix. Names which are bound in the environment and are also the names of builtins are only returned once.
+func completions(eval *evaluator.Evaluator, env *object.Environment, prefix string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, candidates := range [][]string{token.Keywords(), eval.BuiltinNames(), env.Names()} {
+		for _, name := range candidates {
+			if strings.HasPrefix(name, prefix) && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printTokens prints each of the tokens in the source on a separate line, stopping at the first malformed token.
+func printTokens(out io.Writer, src string) {
+	tokens := lexer.NewScanner(lexer.New(src))
+	for tokens.Scan() {
+		fmt.Fprintln(out, tokens.Token())
+	}
+	if err := tokens.Err(); err != nil {
+		fmt.Fprintf(out, "error: %s\n", err)
+	}
+}
+
+// readContinuationLines reads lines from the scanner and appends them to src whilst src is incomplete, prompting for
+// each one. It returns the complete source and false if the end of the input was reached before the source was
+// completed.
+func readContinuationLines(scanner *bufio.Scanner, out io.Writer, src string) (string, bool) {
+	for isIncomplete(src) {
+		fmt.Fprint(out, continuationPrompt)
+		if !scanner.Scan() {
+			return "", false
+		}
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		src += "\n" + line
+	}
+	return src, true
+}
+
+// isIncomplete returns whether the given source would be continued on the next line. This is the case if it contains
+// an opening bracket which hasn't been closed or a string literal or block comment which hasn't been terminated, or if
+// parsing it first fails at the end of the source, like let x = or if (x) would.
+func isIncomplete(src string) bool {
+	l := lexer.New(src)
+	l.CollectErrors()
+	depth := 0
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			return false
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+		switch tok.Type {
+		case token.LParen, token.LBrace, token.LBracket:
+			depth++
+		case token.RParen, token.RBrace, token.RBracket:
+			depth--
+		}
+	}
+	for _, err := range l.Errors() {
+		switch err.(type) {
+		case *lexer.UnterminatedStringError, *lexer.UnterminatedCommentError:
+			return true
+		}
+	}
+	if depth != 0 {
+		return depth > 0
+	}
+	p := parser.New(lexer.New(src))
+	p.ParseProgram()
+	errs := p.Errors()
+	return len(errs) > 0 && strings.HasSuffix(errs[0].Message, "got EOF")
+}
+
+// scannerReader is an [io.Reader] which reads the lines from a [bufio.Scanner]. Builtins like gets read their input
+// through it so that they read the lines following the input which called them, rather than reading from the
+// underlying reader which the scanner may have already buffered past.
+type scannerReader struct {
+	scanner *bufio.Scanner
+	buf     []byte
+}
+
+// Read reads at most one line from the scanner, including its trailing newline.
+func (r *scannerReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
 		}
+		r.buf = append(r.buf, r.scanner.Bytes()...)
+		r.buf = append(r.buf, '\n')
 	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
 }
@@ -0,0 +1,262 @@
+package repl_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/repl"
+)
+
+func TestStart(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      string
+		wantOut string
+	}{
+		{
+			name:    "Expression",
+			in:      "1 + 2 * 3\n",
+			wantOut: "> 7\n> ",
+		},
+		{
+			name: "BindingsPersistAcrossLines",
+			in: `let x = 5
+x + 1
+let double = fn(n) { n * 2 }
+double(x)
+`,
+			wantOut: "> null\n> 6\n> null\n> 10\n> ",
+		},
+		{
+			name:    "GetsReadsFollowingLine",
+			in:      "let line = gets()\nhello world\nline\ngets()\n",
+			wantOut: "> null\n> hello world\n> null\n> ",
+		},
+		{
+			name:    "Reset",
+			in:      "let x = 5\nx\n:reset\nx\n",
+			wantOut: "> null\n> 5\n> > error: identifier not found: x\n> ",
+		},
+		{
+			name:    "ResetWithSurroundingWhitespace",
+			in:      "let x = 5\n  :reset  \nx\n",
+			wantOut: "> null\n> > error: identifier not found: x\n> ",
+		},
+		{
+			name:    "Puts",
+			in:      `puts("hello")` + "\n",
+			wantOut: "> hello\nnull\n> ",
+		},
+		{
+			name:    "ParseErrors",
+			in:      "let = 5; let x 1\n2\n",
+			wantOut: "> error: 1:5: expected IDENT, got ASSIGN(\"=\")\nerror: 1:16: expected ASSIGN, got INT(\"1\")\n> 2\n> ",
+		},
+		{
+			name:    "EvaluationError",
+			in:      "5 + true\n6\n",
+			wantOut: "> error: type mismatch: INTEGER + BOOLEAN\n> 6\n> ",
+		},
+		{
+			name: "MultiLineFunction",
+			in: `let add = fn(x, y) {
+  x + y
+}
+add(1, 2)
+`,
+			wantOut: "> ... ... null\n> 3\n> ",
+		},
+		{
+			name: "MultiLineIfElse",
+			in: `if (1 > 2) {
+  10
+} else {
+  20
+}
+`,
+			wantOut: "> ... ... ... ... 20\n> ",
+		},
+		{
+			name: "MultiLineArray",
+			in: `[1,
+2]
+`,
+			wantOut: "> ... [1, 2]\n> ",
+		},
+		{
+			name:    "MultiLineString",
+			in:      "\"a\nb\"\n",
+			wantOut: "> ... a\nb\n> ",
+		},
+		{
+			name:    "MultiLineBlockComment",
+			in:      "/* a\n b */ 5\n",
+			wantOut: "> ... 5\n> ",
+		},
+		{
+			name:    "MultiLineLet",
+			in:      "let x =\n5\nx\n",
+			wantOut: "> ... null\n> 5\n> ",
+		},
+		{
+			name:    "MultiLineIfCondition",
+			in:      "if (true)\n{ 1 }\n",
+			wantOut: "> ... 1\n> ",
+		},
+		{
+			name:    "MultiLineElse",
+			in:      "if (false) { 1 } else\n{ 2 }\n",
+			wantOut: "> ... 2\n> ",
+		},
+		{
+			name:    "MultiLineInfixExpression",
+			in:      "1 +\n2\n",
+			wantOut: "> ... 3\n> ",
+		},
+		{
+			name: "ErrorBeforeEndOfInputIsNotContinued",
+			in:   "1 + ; let x =\n",
+			wantOut: "> error: 1:5: expected start of expression, got SEMICOLON(\";\")\n" +
+				"error: 1:14: expected start of expression, got EOF\n> ",
+		},
+		{
+			name:    "EmptyLineEndsIncompleteInput",
+			in:      "fn(x) {\n\n1\n",
+			wantOut: "> ... error: 1:8: expected R_BRACE, got EOF\n> 1\n> ",
+		},
+		{
+			name:    "EndOfInputDuringIncompleteInput",
+			in:      "fn(x) {\n",
+			wantOut: "> ... ",
+		},
+		{
+			name:    "ExtraClosingBracket",
+			in:      "1)\n2\n",
+			wantOut: "> error: 1:2: expected start of expression, got R_PAREN(\")\")\n> 2\n> ",
+		},
+		{
+			name:    "Quit",
+			in:      "1\n:quit\n2\n",
+			wantOut: "> 1\n> ",
+		},
+		{
+			name:    "Exit",
+			in:      "1\n:exit\n2\n",
+			wantOut: "> 1\n> ",
+		},
+		{
+			name: "Help",
+			in:   ":help\n",
+			wantOut: `> Enter Monkey source code to evaluate it, or one of the following commands:
+  :help                  print this help
+  :env                   print the current bindings
+  :reset                 clear the current bindings
+  :complete [prefix]     print the keywords, builtins, and bindings starting with prefix
+  :mode lex|parse|eval   print the tokens, AST, or value of each input (default eval)
+  :quit, :exit           exit the REPL
+> `,
+		},
+		{
+			name:    "Env",
+			in:      "let b = \"two\"\nlet a = [1]\nlet f = fn(x) { x }\n:env\n",
+			wantOut: "> null\n> null\n> null\n> a = [1]\nb = two\nf = fn(x) { x; }\n> ",
+		},
+		{
+			name:    "EnvEmpty",
+			in:      ":env\n",
+			wantOut: "> > ",
+		},
+		{
+			name:    "Complete",
+			in:      "let foo = 1\nlet bar = 2\nlet f = 3\n:complete f\n",
+			wantOut: "> null\n> null\n> null\n> f\nfalse\nfilter\nfirst\nflatten\nfn\nfoo\nfor\n> ",
+		},
+		{
+			name:    "CompleteShadowedBuiltin",
+			in:      "let len = 1\n:complete le\n",
+			wantOut: "> null\n> len\nlet\n> ",
+		},
+		{
+			name:    "CompleteNoMatches",
+			in:      ":complete zzz\n",
+			wantOut: "> > ",
+		},
+		{
+			name:    "CompleteAfterReset",
+			in:      "let foo = 1\n:reset\n:complete foo\n",
+			wantOut: "> null\n> > > ",
+		},
+		{
+			name:    "UnknownCommand",
+			in:      ":foo\n",
+			wantOut: "> error: unknown command :foo, enter :help for the list of commands\n> ",
+		},
+		{
+			name: "LexMode",
+			in:   ":mode lex\nlet x = 1 + 2;\n",
+			wantOut: `> > LET("let")
+IDENT("x")
+ASSIGN("=")
+INT("1")
+PLUS("+")
+INT("2")
+SEMICOLON(";")
+> `,
+		},
+		{
+			name:    "LexModeError",
+			in:      ":mode lex\n1 2__0\n",
+			wantOut: "> > INT(\"1\")\nerror: 1:3: invalid number literal \"2__\": consecutive underscores\n> ",
+		},
+		{
+			name:    "ParseMode",
+			in:      ":mode parse\nlet x = 1 + 2;\n-a * b\n",
+			wantOut: "> > let x = (1 + 2);\n> ((-a) * b);\n> ",
+		},
+		{
+			name:    "ParseModeError",
+			in:      ":mode parse\nlet = 1\n",
+			wantOut: "> > error: 1:5: expected IDENT, got ASSIGN(\"=\")\n> ",
+		},
+		{
+			name:    "ParseModeDoesNotEvaluate",
+			in:      ":mode parse\nlet x = 1\n:mode eval\nx\n",
+			wantOut: "> > let x = 1;\n> > error: identifier not found: x\n> ",
+		},
+		{
+			name:    "EvalMode",
+			in:      ":mode lex\n:mode eval\nlet x = 1 + 2;\nx\n",
+			wantOut: "> > > null\n> 3\n> ",
+		},
+		{
+			name:    "ModeWithoutArgument",
+			in:      ":mode\n:mode parse\n:mode\n",
+			wantOut: "> mode: eval\n> > mode: parse\n> ",
+		},
+		{
+			name:    "UnknownMode",
+			in:      ":mode foo\n1\n",
+			wantOut: "> error: unknown mode foo, want one of lex, parse, or eval\n> 1\n> ",
+		},
+		{
+			name:    "NoInput",
+			in:      "",
+			wantOut: "> ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			repl.Start(strings.NewReader(tc.in), out)
+
+			if diff := cmp.Diff(tc.wantOut, out.String()); diff != "" {
+				t.Fatalf("Start() wrote incorrect output for input %q\ndiff:\n--- want\n+++ got\n%s", tc.in, diff)
+			}
+		})
+	}
+}
@@ -1,43 +1,80 @@
 // Package token contains the types of tokens that can be present in Monkey source code.
 package token
 
+import (
+	"fmt"
+	"sort"
+)
+
 // TokenType is the type of a token.
 type TokenType string
 
-// Token represents a token. It stores the type of the token as well as its literal value.
+// Token represents a token. It stores the type of the token, its literal value, and its position in the source code.
+// Tokens are marshalled to JSON as objects with the keys type, literal, line, and column.
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type    TokenType `json:"type"`
+	Literal string    `json:"literal"`
+	// Line and Column are the 1-based position of the first character of the token in the source code.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String returns the type of the token followed by its quoted literal value, like INT("5"). Tokens with empty literal
+// values, like EOF, are returned as just their type.
+func (t Token) String() string {
+	if t.Literal == "" {
+		return string(t.Type)
+	}
+	return fmt.Sprintf("%s(%q)", t.Type, t.Literal)
 }
 
 const (
 	Illegal TokenType = "ILLEGAL"
 	EOF     TokenType = "EOF"
+	Comment TokenType = "COMMENT" // only returned by lexers which are emitting comments
 
 	// Identifiers and literals
-	Ident TokenType = "IDENT" // add, foobar, x, y
-	Int   TokenType = "INT"   // 1, 2, 234234
+	Ident  TokenType = "IDENT"  // add, foobar, x, y
+	Int    TokenType = "INT"    // 1, 2, 234234
+	Float  TokenType = "FLOAT"  // 0.5, 3.14
+	String TokenType = "STRING" // "foo", "hello world", `raw`
+	Char   TokenType = "CHAR"   // 'a', '\n'
 
 	// Operators
-	Assign   TokenType = "ASSIGN"
-	Plus     TokenType = "PLUS"
-	Minus    TokenType = "MINUS"
-	Slash    TokenType = "SLASH"
-	Asterisk TokenType = "ASTERISK"
-	Bang     TokenType = "BANG"
-	Less     TokenType = "LESS"
-	Greater  TokenType = "GREATER"
-	Equal    TokenType = "EQUAL"
-	NotEqual TokenType = "NOT_EQUAL"
+	Assign       TokenType = "ASSIGN"
+	Plus         TokenType = "PLUS"
+	Minus        TokenType = "MINUS"
+	Slash        TokenType = "SLASH"
+	Asterisk     TokenType = "ASTERISK"
+	Percent      TokenType = "PERCENT"
+	Bang         TokenType = "BANG"
+	Less         TokenType = "LESS"
+	Greater      TokenType = "GREATER"
+	LessEqual    TokenType = "LESS_EQUAL"
+	GreaterEqual TokenType = "GREATER_EQUAL"
+	Equal        TokenType = "EQUAL"
+	NotEqual     TokenType = "NOT_EQUAL"
+	And          TokenType = "AND"
+	Or           TokenType = "OR"
+
+	// Compound assignment operators
+	PlusAssign     TokenType = "PLUS_ASSIGN"
+	MinusAssign    TokenType = "MINUS_ASSIGN"
+	AsteriskAssign TokenType = "ASTERISK_ASSIGN"
+	SlashAssign    TokenType = "SLASH_ASSIGN"
 
 	// Delimiters
 	Comma     TokenType = "COMMA"
 	Semicolon TokenType = "SEMICOLON"
+	Colon     TokenType = "COLON"
+	Dot       TokenType = "DOT"
 
-	LParen TokenType = "L_PAREN"
-	RParen TokenType = "R_PAREN"
-	LBrace TokenType = "L_BRACE"
-	RBrace TokenType = "R_BRACE"
+	LParen   TokenType = "L_PAREN"
+	RParen   TokenType = "R_PAREN"
+	LBrace   TokenType = "L_BRACE"
+	RBrace   TokenType = "R_BRACE"
+	LBracket TokenType = "L_BRACKET"
+	RBracket TokenType = "R_BRACKET"
 
 	// Keywords
 	Function TokenType = "FUNCTION"
@@ -47,16 +84,51 @@ const (
 	Else     TokenType = "ELSE"
 	True     TokenType = "TRUE"
 	False    TokenType = "FALSE"
+	While    TokenType = "WHILE"
+	For      TokenType = "FOR"
+	Break    TokenType = "BREAK"
+	Continue TokenType = "CONTINUE"
+	Null     TokenType = "NULL"
+	Macro    TokenType = "MACRO"
 )
 
 var keywordTokenTypesByIdent = map[string]TokenType{
-	"fn":     Function,
-	"return": Return,
-	"let":    Let,
-	"if":     If,
-	"else":   Else,
-	"true":   True,
-	"false":  False,
+	"fn":       Function,
+	"return":   Return,
+	"let":      Let,
+	"if":       If,
+	"else":     Else,
+	"true":     True,
+	"false":    False,
+	"while":    While,
+	"for":      For,
+	"break":    Break,
+	"continue": Continue,
+	"null":     Null,
+	"macro":    Macro,
+}
+
+// operatorTokenTypes contains the token types which are operators.
+var operatorTokenTypes = map[TokenType]bool{
+	Assign:         true,
+	Plus:           true,
+	Minus:          true,
+	Slash:          true,
+	Asterisk:       true,
+	Percent:        true,
+	Bang:           true,
+	Less:           true,
+	Greater:        true,
+	LessEqual:      true,
+	GreaterEqual:   true,
+	Equal:          true,
+	NotEqual:       true,
+	And:            true,
+	Or:             true,
+	PlusAssign:     true,
+	MinusAssign:    true,
+	AsteriskAssign: true,
+	SlashAssign:    true,
 }
 
 // IdentTokenType returns the token type of the given identifier. Identifiers can either be regular identifiers or they
@@ -67,3 +139,28 @@ func IdentTokenType(ident string) TokenType {
 	}
 	return Ident
 }
+
+// IsKeyword returns whether the token type is the type of a keyword.
+func (t TokenType) IsKeyword() bool {
+	for _, tokenType := range keywordTokenTypesByIdent {
+		if tokenType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOperator returns whether the token type is the type of an operator.
+func (t TokenType) IsOperator() bool {
+	return operatorTokenTypes[t]
+}
+
+// Keywords returns the sorted identifiers which are keywords.
+func Keywords() []string {
+	keywords := make([]string, 0, len(keywordTokenTypesByIdent))
+	for keyword := range keywordTokenTypesByIdent {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
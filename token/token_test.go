@@ -0,0 +1,148 @@
+package token_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/token"
+)
+
+func TestTokenString(t *testing.T) {
+	testCases := []struct {
+		name  string
+		token token.Token
+		want  string
+	}{
+		{
+			name:  "Identifier",
+			token: token.Token{Type: token.Ident, Literal: "foo"},
+			want:  `IDENT("foo")`,
+		},
+		{
+			name:  "Integer",
+			token: token.Token{Type: token.Int, Literal: "5"},
+			want:  `INT("5")`,
+		},
+		{
+			name:  "Operator",
+			token: token.Token{Type: token.Plus, Literal: "+"},
+			want:  `PLUS("+")`,
+		},
+		{
+			name:  "Illegal",
+			token: token.Token{Type: token.Illegal, Literal: `\`},
+			want:  `ILLEGAL("\\")`,
+		},
+		{
+			name:  "EOF",
+			token: token.Token{Type: token.EOF},
+			want:  "EOF",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.token.String(); got != tc.want {
+				t.Fatalf("%#v.String() = %q, want %q", tc.token, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTokenJSON(t *testing.T) {
+	tok := token.Token{Type: token.String, Literal: "foo", Line: 2, Column: 5}
+	want := `{"type":"STRING","literal":"foo","line":2,"column":5}`
+
+	b, err := json.Marshal(tok)
+	if err != nil {
+		t.Fatalf("json.Marshal(%#v) returned unexpected error: %s", tok, err)
+	}
+	if got := string(b); got != want {
+		t.Fatalf("json.Marshal(%#v) = %s, want %s", tok, got, want)
+	}
+
+	var got token.Token
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned unexpected error: %s", b, err)
+	}
+	if diff := cmp.Diff(tok, got); diff != "" {
+		t.Fatalf("json.Unmarshal(%s) returned incorrect token\ndiff:\n--- want\n+++ got\n%s", b, diff)
+	}
+}
+
+func TestIsKeyword(t *testing.T) {
+	testCases := []struct {
+		tokenType token.TokenType
+		want      bool
+	}{
+		{tokenType: token.Let, want: true},
+		{tokenType: token.Function, want: true},
+		{tokenType: token.Null, want: true},
+		{tokenType: token.Macro, want: true},
+		{tokenType: token.Ident, want: false},
+		{tokenType: token.Int, want: false},
+		{tokenType: token.Plus, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.tokenType), func(t *testing.T) {
+			if got := tc.tokenType.IsKeyword(); got != tc.want {
+				t.Fatalf("%s.IsKeyword() = %t, want %t", tc.tokenType, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsOperator(t *testing.T) {
+	testCases := []struct {
+		tokenType token.TokenType
+		want      bool
+	}{
+		{tokenType: token.Assign, want: true},
+		{tokenType: token.Plus, want: true},
+		{tokenType: token.Minus, want: true},
+		{tokenType: token.Slash, want: true},
+		{tokenType: token.Asterisk, want: true},
+		{tokenType: token.Percent, want: true},
+		{tokenType: token.Bang, want: true},
+		{tokenType: token.Less, want: true},
+		{tokenType: token.Greater, want: true},
+		{tokenType: token.LessEqual, want: true},
+		{tokenType: token.GreaterEqual, want: true},
+		{tokenType: token.Equal, want: true},
+		{tokenType: token.NotEqual, want: true},
+		{tokenType: token.And, want: true},
+		{tokenType: token.Or, want: true},
+		{tokenType: token.PlusAssign, want: true},
+		{tokenType: token.SlashAssign, want: true},
+		{tokenType: token.Ident, want: false},
+		{tokenType: token.Int, want: false},
+		{tokenType: token.Comma, want: false},
+		{tokenType: token.LParen, want: false},
+		{tokenType: token.Let, want: false},
+		{tokenType: token.EOF, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.tokenType), func(t *testing.T) {
+			if got := tc.tokenType.IsOperator(); got != tc.want {
+				t.Fatalf("%s.IsOperator() = %t, want %t", tc.tokenType, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	want := []string{"break", "continue", "else", "false", "fn", "for", "if", "let", "macro", "null", "return", "true", "while"}
+	got := token.Keywords()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Keywords() returned incorrect keywords\ndiff:\n--- want\n+++ got\n%s", diff)
+	}
+	for _, keyword := range got {
+		if !token.IdentTokenType(keyword).IsKeyword() {
+			t.Errorf("IdentTokenType(%q).IsKeyword() = false, want true", keyword)
+		}
+	}
+}
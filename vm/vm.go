@@ -0,0 +1,115 @@
+// Package vm contains a stack-based virtual machine which executes the [compiler.Bytecode] produced by the [compiler]
+// package.
+package vm
+
+import (
+	"fmt"
+
+	"github.com/marcuscaisey/monkey/code"
+	"github.com/marcuscaisey/monkey/compiler"
+	"github.com/marcuscaisey/monkey/object"
+)
+
+// stackSize is the maximum number of values which can be on the stack at once.
+const stackSize = 2048
+
+// VM executes bytecode instructions against a stack of operands.
+type VM struct {
+	constants    []object.Object
+	instructions code.Instructions
+
+	stack []object.Object
+	// sp points to the next free slot in the stack. The value on top of the stack is stack[sp-1].
+	sp int
+}
+
+// New returns a VM which will execute the given bytecode.
+func New(bytecode *compiler.Bytecode) *VM {
+	return &VM{
+		constants:    bytecode.Constants,
+		instructions: bytecode.Instructions,
+		stack:        make([]object.Object, stackSize),
+		sp:           0,
+	}
+}
+
+// StackTop returns the value on top of the stack or nil if the stack is empty.
+func (vm *VM) StackTop() object.Object {
+	if vm.sp == 0 {
+		return nil
+	}
+	return vm.stack[vm.sp-1]
+}
+
+// LastPoppedStackElem returns the value which was most recently popped off the stack. This is the value of the last
+// expression statement once [VM.Run] has returned.
+func (vm *VM) LastPoppedStackElem() object.Object {
+	return vm.stack[vm.sp]
+}
+
+// Run executes the instructions.
+func (vm *VM) Run() error {
+	for ip := 0; ip < len(vm.instructions); ip++ {
+		op := code.Opcode(vm.instructions[ip])
+		switch op {
+		case code.OpConstant:
+			constIndex := code.ReadUint16(vm.instructions[ip+1:])
+			ip += 2
+			if err := vm.push(vm.constants[constIndex]); err != nil {
+				return err
+			}
+		case code.OpAdd, code.OpSub, code.OpMul, code.OpDiv:
+			if err := vm.executeBinaryOperation(op); err != nil {
+				return err
+			}
+		case code.OpPop:
+			vm.pop()
+		default:
+			return fmt.Errorf("unknown opcode %d", op)
+		}
+	}
+	return nil
+}
+
+func (vm *VM) executeBinaryOperation(op code.Opcode) error {
+	right := vm.pop()
+	left := vm.pop()
+	leftInteger, leftOk := left.(*object.Integer)
+	rightInteger, rightOk := right.(*object.Integer)
+	if !leftOk || !rightOk {
+		return fmt.Errorf("unsupported types for binary operation: %s %s", left.Type(), right.Type())
+	}
+
+	var result int64
+	switch op {
+	case code.OpAdd:
+		result = leftInteger.Value + rightInteger.Value
+	case code.OpSub:
+		result = leftInteger.Value - rightInteger.Value
+	case code.OpMul:
+		result = leftInteger.Value * rightInteger.Value
+	case code.OpDiv:
+		if rightInteger.Value == 0 {
+			return fmt.Errorf("division by zero")
+		}
+		result = leftInteger.Value / rightInteger.Value
+	}
+	return vm.push(&object.Integer{Value: result})
+}
+
+func (vm *VM) push(obj object.Object) error {
+	if vm.sp >= stackSize {
+		return fmt.Errorf("stack overflow")
+	}
+	vm.stack[vm.sp] = obj
+	vm.sp++
+	return nil
+}
+
+// pop removes the value on top of the stack and returns it. The value is left in the slot above the new top of the
+// stack so that it can be returned by LastPoppedStackElem.
+func (vm *VM) pop() object.Object {
+	obj := vm.stack[vm.sp-1]
+	vm.sp--
+	return obj
+}
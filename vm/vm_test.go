@@ -0,0 +1,72 @@
+package vm_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marcuscaisey/monkey/compiler"
+	"github.com/marcuscaisey/monkey/lexer"
+	"github.com/marcuscaisey/monkey/object"
+	"github.com/marcuscaisey/monkey/parser"
+	"github.com/marcuscaisey/monkey/vm"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		src  string
+		want object.Object
+	}{
+		{src: "1", want: &object.Integer{Value: 1}},
+		{src: "1 + 2", want: &object.Integer{Value: 3}},
+		{src: "1 - 2", want: &object.Integer{Value: -1}},
+		{src: "3 * 4", want: &object.Integer{Value: 12}},
+		{src: "9 / 2", want: &object.Integer{Value: 4}},
+		{src: "50 / 2 * 2 + 10 - 5", want: &object.Integer{Value: 55}},
+		{src: "5 * (2 + 10)", want: &object.Integer{Value: 60}},
+		{src: "1; 2", want: &object.Integer{Value: 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			machine := vm.New(compile(t, tc.src))
+			if err := machine.Run(); err != nil {
+				t.Fatalf("Run() returned unexpected error for source %q: %s", tc.src, err)
+			}
+			if got := machine.StackTop(); got != nil {
+				t.Errorf("StackTop() = %v after running source %q, want nil", got, tc.src)
+			}
+			got := machine.LastPoppedStackElem()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("LastPoppedStackElem() returned incorrect value for source %q\ndiff:\n--- want\n+++ got\n%s", tc.src, diff)
+			}
+		})
+	}
+}
+
+func TestRunReturnsError(t *testing.T) {
+	src := "1 / 0"
+	want := "division by zero"
+	err := vm.New(compile(t, src)).Run()
+	if err == nil {
+		t.Fatalf("Run() returned no error for source %q, want %q", src, want)
+	}
+	if got := err.Error(); got != want {
+		t.Fatalf("Run() returned error %q for source %q, want %q", got, src, want)
+	}
+}
+
+// compile compiles the given source, failing the test if it can't be parsed or compiled.
+func compile(t *testing.T, src string) *compiler.Bytecode {
+	t.Helper()
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("ParseProgram() returned unexpected errors for source %q:\n%v", src, errs)
+	}
+	c := compiler.New()
+	if err := c.Compile(program); err != nil {
+		t.Fatalf("Compile() returned unexpected error for source %q: %s", src, err)
+	}
+	return c.Bytecode()
+}